@@ -0,0 +1,67 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type TimeoutConfig struct {
+	IdleTimeout time.Duration `mapstructure:"net_idle_timeout"`
+}
+
+func registerTimeoutConfig(t *testing.T) *TimeoutConfig {
+	t.Helper()
+	conf := &TimeoutConfig{}
+	require.NoError(t, RegisterConfig("net", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "net_idle_timeout", DefaultVal: time.Second, Aliases: []string{"net_timeout"}}},
+	}))
+	return conf
+}
+
+func TestBindValue_Aliases(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		want        time.Duration
+		wantWarning string
+	}{
+		{name: "alias only", env: map[string]string{"HM_NET_TIMEOUT": "250ms"},
+			want: 250 * time.Millisecond, wantWarning: "HM_NET_TIMEOUT is deprecated, use HM_NET_IDLE_TIMEOUT"},
+		{name: "primary wins", env: map[string]string{"HM_NET_TIMEOUT": "250ms", "HM_NET_IDLE_TIMEOUT": "500ms"},
+			want: 500 * time.Millisecond},
+		{name: "primary only", env: map[string]string{"HM_NET_IDLE_TIMEOUT": "500ms"},
+			want: 500 * time.Millisecond},
+		{name: "neither", want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer Reset()
+			resetViper(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			conf := registerTimeoutConfig(t)
+
+			require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithStrictEnv()))
+			assert.Equal(t, tt.want, conf.IdleTimeout)
+			if tt.wantWarning == "" {
+				assert.Empty(t, Warnings())
+				return
+			}
+			require.Len(t, Warnings(), 1)
+			assert.EqualError(t, Warnings()[0], tt.wantWarning)
+		})
+	}
+}
+
+func TestBindValue_EmptyAlias(t *testing.T) {
+	defer Reset()
+	err := RegisterConfig("net", ConfigEntry{
+		Config:    &TimeoutConfig{},
+		BindArray: []BindValue{{ValName: "net_idle_timeout", Aliases: []string{""}}},
+	})
+	assert.ErrorContains(t, err, "BindValue.Aliases of net_idle_timeout cannot contain an empty name")
+}
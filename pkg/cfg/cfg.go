@@ -45,32 +45,36 @@
 //			return
 //		}
 //
-//		// Access the initialized configuration
-//		logConfig, ok := cfg.GetConfig("log")
-//		if !ok {
-//			fmt.Println("Failed to retrieve the logging config.")
+//		// Access the initialized configuration as a typed pointer
+//		logConfig, err := cfg.Get[LoggingConfig]("log")
+//		if err != nil {
+//			fmt.Printf("Failed to retrieve the logging config: %v\n", err)
 //			return
 //		}
-//
-//		// Type assert to the specific configuration struct
-//		if typedConfig, ok := logConfig.(*LoggingConfig); ok {
-//			fmt.Printf("Initialized Logging Config: %+v\n", typedConfig)
-//		} else {
-//			fmt.Println("Failed to type assert logging config.")
-//		}
+//		fmt.Printf("Initialized Logging Config: %+v\n", logConfig)
 //	}
 package cfg
 
 import (
+	"errors"
 	"fmt"
-	"github.com/spf13/viper"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"path/filepath"
 	"reflect"
-	"sync"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
-	configEntries = make(map[string]ConfigEntry)
-	mtx           sync.RWMutex
+	// defaultRegistry backs the package-level functions and uses the global Viper instance.
+	defaultRegistry = newRegistry(nil)
+	// envKeyReplacer maps the dots of nested keys to underscores in environment variable names.
+	envKeyReplacer = strings.NewReplacer(".", "_")
+	// supportedConfigFormats lists the file formats accepted by WithConfigFile.
+	supportedConfigFormats = map[string]struct{}{"yaml": {}, "yml": {}, "json": {}, "toml": {}}
 )
 
 // ConfigEntry represents a registered configuration entry.
@@ -79,6 +83,9 @@ var (
 type ConfigEntry struct {
 	Config    any
 	BindArray []BindValue
+	// EnvPrefix overrides the global prefix set by WithSetEnvPrefix for this entry only.
+	// For example, "AUX" binds log_level to AUX_LOG_LEVEL.
+	EnvPrefix string
 }
 
 // BindValue represents a binding of an environment variable to a configuration field
@@ -86,26 +93,27 @@ type ConfigEntry struct {
 type BindValue struct {
 	ValName    string // Environment variable name
 	DefaultVal any    // Default value for the environment variable
+	Required   bool   // NewConfig fails if the value is not set and has no default
+	// DefaultFunc computes the default at load time, e.g. runtime.NumCPU, when it cannot be static.
+	// DefaultVal must be nil when it is set. Returning an error, panicking or returning a value that
+	// cannot convert to the field type, as checked for DefaultVal, fails the load.
+	DefaultFunc func() any
+	// Aliases are older names of ValName read when the primary variable is not set,
+	// e.g. "net_timeout" while renaming HM_NET_TIMEOUT to HM_NET_IDLE_TIMEOUT.
+	// Setting an alias is reported by Warnings as deprecated.
+	Aliases []string
 }
 
 // ViperOption represents a functional option for configuring the behavior of Viper.
 // It allows for customizing Viper's initialization, such as setting environment variable prefixes.
 // This enables flexible configuration adjustments without altering the core logic.
-type ViperOption func() error
+// The option receives the Registry being loaded.
+type ViperOption func(r *Registry) error
 
 // NewConfig initializes the configuration system, binds all registered configuration entries,
-// and loads their values from environment variables.
+// and loads their values from environment variables and, if WithConfigFile is supplied, a config file.
 func NewConfig(list ...ViperOption) error {
-	for _, opt := range list {
-		err := opt()
-		if err != nil {
-			return err
-		}
-	}
-
-	viper.AutomaticEnv()
-
-	return newConfigWithLock()
+	return defaultRegistry.Load(list...)
 }
 
 // WithSetEnvPrefix sets the environment variable prefix for Viper.
@@ -123,70 +131,219 @@ func NewConfig(list ...ViperOption) error {
 // In this example, Viper will look for environment variables prefixed with "MYAPP_".
 func WithSetEnvPrefix(EnvPrefix string) ViperOption {
 	if EnvPrefix == "" {
-		return func() error {
+		return func(*Registry) error {
 			return fmt.Errorf("incorrect env prefix: %s", EnvPrefix)
 		}
 	}
-	return func() error {
-		viper.SetEnvPrefix(EnvPrefix)
+	return func(r *Registry) error {
+		r.Viper().SetEnvPrefix(EnvPrefix)
+		r.envPrefix = EnvPrefix
 		return nil
 	}
 }
 
-// newConfigWithLock acquires a write lock and iterates through all registered configuration entries
-// to bind and load their values from the environment.
-func newConfigWithLock() error {
-	mtx.Lock()
-	defer mtx.Unlock()
-	for k, v := range configEntries {
-		if err := bindActualValue(&v); err != nil {
-			return fmt.Errorf("failed to bind config %s: %w", k, err)
+// WithFlags declares a flag on fs for every BindValue of the configs registered so far,
+// and makes NewConfig read the parsed flags. Flag names are the ValName with underscores
+// turned into dashes (log_level becomes --log-level), and flag defaults are the DefaultVal.
+// Precedence, from highest to lowest: flags set on the command line, environment variables,
+// config file values, BindValue defaults.
+//
+// Because flags must exist before fs.Parse, WithFlags must be called after RegisterConfig
+// and before fs.Parse. NewConfig returns an error if fs was not parsed.
+//
+// Example Usage:
+//
+//	flags := cfg.WithFlags(pflag.CommandLine)
+//	pflag.Parse()
+//	if err := cfg.NewConfig(flags); err != nil {
+//	    fmt.Printf("Error initializing configs: %v\n", err)
+//	}
+func WithFlags(fs *pflag.FlagSet) ViperOption {
+	return defaultRegistry.WithFlags(fs)
+}
+
+// flagName returns the flag name of a ValName.
+func flagName(valName string) string {
+	return strings.ReplaceAll(valName, "_", "-")
+}
+
+// WithFileSecrets makes NewConfig read values from files referenced by <NAME>_FILE
+// environment variables, following the Docker and Kubernetes secrets convention.
+// For example, HM_DB_URI_FILE=/run/secrets/db_uri sets db_uri to the file contents
+// with the trailing newline trimmed. If the plain variable is set as well, in the environment
+// or in the file of WithEnvFile, it wins and the conflict is reported by Warnings; a flag set
+// with WithFlags wins as well. A missing or unreadable file fails NewConfig.
+//
+// Example Usage:
+//
+//	err := cfg.NewConfig(cfg.WithSetEnvPrefix("HM"), cfg.WithFileSecrets())
+//	if err != nil {
+//	    fmt.Printf("Error initializing configs: %v\n", err)
+//	}
+func WithFileSecrets() ViperOption {
+	return func(r *Registry) error {
+		r.fileSecretsOnLoad.Store(true)
+		return nil
+	}
+}
+
+// Warnings returns the non-fatal problems found by the last NewConfig call,
+// such as a variable set both directly and through a _FILE secret.
+func Warnings() []error {
+	return defaultRegistry.Warnings()
+}
+
+// WithConfigFile reads configuration values from the file at path and merges them with
+// the environment bindings. Environment variables take precedence over file values,
+// and file values take precedence over BindValue defaults.
+// Format must be one of "yaml", "yml", "json" or "toml". If format is empty,
+// it is inferred from the file extension.
+// NewConfig returns an error if the file is missing or cannot be parsed.
+//
+// Example Usage:
+//
+//	err := cfg.NewConfig(cfg.WithConfigFile("config.yaml", "yaml"))
+//	if err != nil {
+//	    fmt.Printf("Error initializing configs: %v\n", err)
+//	}
+func WithConfigFile(path, format string) ViperOption {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format = strings.ToLower(format)
+	return func(r *Registry) error {
+		if path == "" {
+			return fmt.Errorf("config file path cannot be empty")
 		}
+		if _, ok := supportedConfigFormats[format]; !ok {
+			return fmt.Errorf("unsupported config file format: %q", format)
+		}
+		v := r.Viper()
+		v.SetConfigFile(path)
+		v.SetConfigType(format)
+		r.configFileFormat = format
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// WithValidation makes NewConfig validate every registered config struct with the val package
+// once it is loaded. Failures of all entries are returned together as ConfigErrors
+// naming each failing entry and its fields. Configs without validate tags pass untouched.
+//
+// Example Usage:
+//
+//	err := cfg.NewConfig(cfg.WithValidation())
+//	if err != nil {
+//	    fmt.Printf("Invalid configuration: %v\n", err)
+//	}
+func WithValidation() ViperOption {
+	return func(r *Registry) error {
+		r.validateOnLoad.Store(true)
+		return nil
+	}
+}
+
+// WithDecodeHook appends hook to the decode hooks used to unmarshal every config, after the defaults
+// turning strings into time.Duration, comma-separated slices such as []string, and net.IP.
+// The hooks stay in use for Watch reloads and SetValue until the next NewConfig call.
+//
+// Example Usage:
+//
+//	err := cfg.NewConfig(cfg.WithDecodeHook(mapstructure.TextUnmarshallerHookFunc()))
+//	if err != nil {
+//	    fmt.Printf("Error initializing configs: %v\n", err)
+//	}
+func WithDecodeHook(hook mapstructure.DecodeHookFunc) ViperOption {
+	if hook == nil {
+		return func(*Registry) error {
+			return fmt.Errorf("decode hook cannot be nil")
+		}
+	}
+	return func(r *Registry) error {
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		r.decodeHooks = append(r.decodeHooks, hook)
+		return nil
 	}
-	return nil
 }
 
 // RegisterConfig allows clients to register their custom configuration structs
 // along with their environment variable bindings.
+// It returns an error if name is already registered, so two modules cannot silently share a name.
+// Configs are usually all registered before NewConfig. Once NewConfig has run, RegisterConfig
+// loads the new entry at once as LoadEntry would, and does not register it if that fails.
 func RegisterConfig(name string, configStruct ConfigEntry) error {
-	if name == "" {
-		return fmt.Errorf("name cannot be empty")
-	}
+	return defaultRegistry.Register(name, configStruct)
+}
 
-	if err := validateConfigStruct(configStruct); err != nil {
-		return fmt.Errorf("config validation failed: %w", err)
-	}
+// LoadEntry binds and unmarshals only the configuration entry registered as name, validating it
+// if the last NewConfig call used WithValidation. It loads a single entry on demand without
+// re-running NewConfig for everything.
+func LoadEntry(name string) error {
+	return defaultRegistry.LoadEntry(name)
+}
 
-	if err := validateBindArray(configStruct.BindArray); err != nil {
-		return fmt.Errorf("bind array validation failed: %w", err)
-	}
+// RegisterConfigOverride registers a configuration struct like RegisterConfig, intentionally
+// replacing any entry already registered under name together with its SetValue overrides.
+func RegisterConfigOverride(name string, configStruct ConfigEntry) error {
+	return defaultRegistry.RegisterOverride(name, configStruct)
+}
 
-	storeConfigStructWithLock(name, configStruct)
-	return nil
+// UnregisterConfig removes a registered configuration entry by name.
+// It returns an error if no entry is registered under name.
+func UnregisterConfig(name string) error {
+	return defaultRegistry.Unregister(name)
+}
+
+// Reset removes all registered configuration entries together with the values set by SetValue,
+// and resets the global Viper instance holding the environment prefix and config file,
+// so configs can be registered and loaded again from scratch.
+// It is primarily intended for test isolation.
+func Reset() {
+	defaultRegistry.Reset()
 }
 
 // ListConfigs returns a list of all registered configuration names.
 func ListConfigs() []string {
-	mtx.RLock()
-	defer mtx.RUnlock()
-	keys := make([]string, 0, len(configEntries))
-	for k := range configEntries {
-		keys = append(keys, k)
-	}
-	return keys
+	return defaultRegistry.List()
+}
+
+// EntryInfo describes a registered configuration entry.
+type EntryInfo struct {
+	Name       string // Registered config name
+	Type       string // Concrete struct type, e.g. "genCfg.HttpConfig"
+	BindValues int    // Number of BindValues
+	Loaded     bool   // Whether NewConfig has loaded the entry since it was registered
+}
+
+// ListEntries returns the metadata of all registered configurations, ordered by name.
+// It allows generic tooling, such as a /debug/config endpoint, to inspect the registry
+// without knowing every config name.
+func ListEntries() []EntryInfo {
+	return defaultRegistry.ListEntries()
 }
 
 // GetConfig retrieves a registered configuration struct by name.
+// It is the fast path returning the pointer stored in the registry, shared by all callers:
+// do not mutate it, and use GetConfigCopy when a private copy is needed.
 func GetConfig(name string) (any, bool) {
-	v, ok := getConfigWithRLock(name)
-	return v.Config, ok
+	return defaultRegistry.Get(name)
 }
 
-// storeConfigStructWithLock safely stores a configuration struct in the registry with a write lock.
-func storeConfigStructWithLock(name string, configStruct ConfigEntry) {
-	mtx.Lock()
-	defer mtx.Unlock()
-	configEntries[name] = configStruct
+// Get retrieves a registered configuration struct by name as a *T.
+// It returns an error if name is not registered or if the stored Config is not a *T.
+//
+// Example usage:
+//
+//	logConfig, err := cfg.Get[LoggingConfig]("log")
+//	if err != nil {
+//		// Handle error
+//	}
+func Get[T any](name string) (*T, error) {
+	return GetFrom[T](defaultRegistry, name)
 }
 
 // validateBindArray ensures that all BindValues in the array have non-empty ValName fields.
@@ -195,16 +352,100 @@ func validateBindArray(bindArray []BindValue) error {
 		if bind.ValName == "" {
 			return fmt.Errorf("BindValue.ValName cannot be empty")
 		}
+		if bind.DefaultFunc != nil && bind.DefaultVal != nil {
+			return fmt.Errorf("BindValue %s cannot set both DefaultVal and DefaultFunc", bind.ValName)
+		}
+		if slices.Contains(bind.Aliases, "") {
+			return fmt.Errorf("BindValue.Aliases of %s cannot contain an empty name", bind.ValName)
+		}
 	}
 	return nil
 }
 
-// getConfigWithRLock retrieves a registered configuration struct by name with a read lock.
-func getConfigWithRLock(name string) (ConfigEntry, bool) {
-	mtx.RLock()
-	defer mtx.RUnlock()
-	v, ok := configEntries[name]
-	return v, ok
+// validateDefaults ensures that every BindValue.DefaultVal can be decoded into the struct field
+// carrying the matching mapstructure tag. Bindings without a matching field are not checked.
+func validateDefaults(config ConfigEntry) error {
+	t := reflect.TypeOf(config.Config).Elem()
+	fields := fieldsByKey(t)
+
+	var errs []error
+	for _, b := range config.BindArray {
+		if err := checkDefault(fields, b.ValName, b.DefaultVal); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkDefault returns an error naming key if def cannot be decoded into the field of fields
+// matching key. Nil defaults and keys without a matching field are not checked.
+func checkDefault(fields map[string]fieldDoc, key string, def any) error {
+	f, ok := fields[strings.ToLower(key)]
+	if !ok || def == nil || defaultConvertible(def, f.typ) {
+		return nil
+	}
+	return fmt.Errorf("default of %s is %T, cannot convert to %s (field %s)", key, def, f.typ, f.path)
+}
+
+// defaultConvertible reports whether viper can decode def into a field of type typ.
+// Strings must parse as the field type, numbers convert between numeric kinds,
+// and scalars convert to strings.
+func defaultConvertible(def any, typ reflect.Type) bool {
+	dv := reflect.ValueOf(def)
+	if dv.Type().AssignableTo(typ) {
+		return true
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	s, isString := def.(string)
+
+	if typ == reflect.TypeOf(time.Duration(0)) {
+		if isString {
+			_, err := time.ParseDuration(s)
+			return err == nil
+		}
+		return isIntKind(dv.Kind())
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return isIntKind(dv.Kind()) || isFloatKind(dv.Kind()) || dv.Kind() == reflect.Bool || dv.Kind() == reflect.String
+	case reflect.Bool:
+		if isString {
+			_, err := strconv.ParseBool(s)
+			return err == nil
+		}
+		return dv.Kind() == reflect.Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if isString {
+			_, err := strconv.ParseInt(s, 0, 64)
+			return err == nil
+		}
+		return isIntKind(dv.Kind()) || isFloatKind(dv.Kind())
+	case reflect.Float32, reflect.Float64:
+		if isString {
+			_, err := strconv.ParseFloat(s, 64)
+			return err == nil
+		}
+		return isIntKind(dv.Kind()) || isFloatKind(dv.Kind())
+	case reflect.Slice:
+		// a string default is split into the slice elements
+		return isString || dv.Type().ConvertibleTo(typ)
+	default:
+		return dv.Type().ConvertibleTo(typ)
+	}
+}
+
+// isIntKind reports whether k is a signed or unsigned integer kind.
+func isIntKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Int64) || (k >= reflect.Uint && k <= reflect.Uint64)
+}
+
+// isFloatKind reports whether k is a floating point kind.
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
 }
 
 // isNotNullOrDefault checks if a value is not nil and not its default value.
@@ -229,17 +470,35 @@ func isNotNullOrDefault(value any) bool {
 	return !reflect.DeepEqual(val.Interface(), zero)
 }
 
-// bindToEnv binds environment variables and sets default values for a configuration entry.
-func bindToEnv(entry *ConfigEntry) error {
-	for _, e := range entry.BindArray {
-		if err := viper.BindEnv(e.ValName); err != nil {
-			return fmt.Errorf("failed to bind %s: %w", e.ValName, err)
-		}
-		if isNotNullOrDefault(e.DefaultVal) {
-			viper.SetDefault(e.ValName, e.DefaultVal)
-		}
+// envName returns the environment variable viper reads for key under prefix.
+// Dots of nested keys become underscores, so db.pool.max_open is read from DB_POOL_MAX_OPEN.
+func envName(prefix, key string) string {
+	if prefix != "" {
+		key = prefix + "_" + key
 	}
-	return nil
+	return envKeyReplacer.Replace(strings.ToUpper(key))
+}
+
+// AutoBind returns a BindValue without default for every leaf field of the struct config points to,
+// in declaration order. Squashed structs share the keys of their parent and other nested structs
+// add their mapstructure key as a dotted prefix, e.g. db.pool.max_open read from HM_DB_POOL_MAX_OPEN.
+// It returns nil if config is not a pointer to a struct.
+//
+// Example usage:
+//
+//	conf := &ServiceConfig{}
+//	err := cfg.RegisterConfig("svc", cfg.ConfigEntry{Config: conf, BindArray: cfg.AutoBind(conf)})
+func AutoBind(config any) []BindValue {
+	t := reflect.TypeOf(config)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	fields := collectFields(t.Elem(), t.Elem().Name(), "", nil, nil)
+	binds := make([]BindValue, 0, len(fields))
+	for _, f := range fields {
+		binds = append(binds, BindValue{ValName: f.key})
+	}
+	return binds
 }
 
 // validateConfigStruct validates that the Config field of a ConfigEntry is a pointer to a struct.
@@ -250,17 +509,3 @@ func validateConfigStruct(config ConfigEntry) error {
 	}
 	return nil
 }
-
-// bindActualValue binds environment variables to a configuration entry and unmarshals
-// the environment values into the Config field.
-func bindActualValue(entry *ConfigEntry) error {
-	if err := bindToEnv(entry); err != nil {
-		return err
-	}
-
-	if err := viper.Unmarshal(entry.Config); err != nil {
-		return fmt.Errorf("failed to unmarshal into config: %w", err)
-	}
-
-	return nil
-}
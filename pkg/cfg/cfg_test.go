@@ -1,8 +1,13 @@
 package cfg
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -11,14 +16,8 @@ type TestConfig struct {
 	Field2 int    `mapstructure:"field2" validate:"numeric"`
 }
 
-func resetConfigEntries() {
-	mtx.Lock()
-	defer mtx.Unlock()
-	configEntries = make(map[string]ConfigEntry)
-}
-
 func TestRegisterConfig_ValidConfig(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	entry := ConfigEntry{
 		Config: &TestConfig{},
@@ -36,7 +35,7 @@ func TestRegisterConfig_ValidConfig(t *testing.T) {
 }
 
 func TestRegisterConfig_InvalidConfig(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	entry := ConfigEntry{
 		Config: "invalid",
@@ -50,8 +49,47 @@ func TestRegisterConfig_InvalidConfig(t *testing.T) {
 	assert.Contains(t, err.Error(), "config validation failed", "expected config validation error")
 }
 
+func TestRegisterConfig_Duplicate(t *testing.T) {
+	defer Reset()
+	first := &TestConfig{}
+	require.NoError(t, RegisterConfig("log", ConfigEntry{Config: first}))
+
+	err := RegisterConfig("log", ConfigEntry{Config: &OtherConfig{}})
+	assert.EqualError(t, err, "config 'log' is already registered")
+
+	conf, ok := GetConfig("log")
+	require.True(t, ok)
+	assert.Same(t, first, conf, "expected the first registration to be kept")
+	assert.Equal(t, []string{"log"}, ListConfigs())
+}
+
+func TestRegisterConfigOverride(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level"}},
+	}))
+	require.NoError(t, SetValue("log", "log_level", "debug"))
+
+	replacement := &LevelConfig{}
+	require.NoError(t, RegisterConfigOverride("log", ConfigEntry{
+		Config:    replacement,
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+	}))
+	require.NoError(t, RegisterConfigOverride("new", ConfigEntry{Config: &OtherConfig{}}),
+		"expected override of an unregistered name to register it")
+	require.NoError(t, NewConfig())
+
+	conf, err := Get[LevelConfig]("log")
+	require.NoError(t, err)
+	assert.Same(t, replacement, conf)
+	assert.Equal(t, "info", conf.Level, "expected the replaced entry's overrides to be dropped")
+	assert.ElementsMatch(t, []string{"log", "new"}, ListConfigs())
+}
+
 func TestRegisterConfig_EmptyName(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	entry := ConfigEntry{
 		Config:    &TestConfig{},
@@ -64,7 +102,7 @@ func TestRegisterConfig_EmptyName(t *testing.T) {
 }
 
 func TestListConfigs(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	entry1 := ConfigEntry{
 		Config:    &TestConfig{},
@@ -83,7 +121,7 @@ func TestListConfigs(t *testing.T) {
 }
 
 func TestGetConfig(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	entry := ConfigEntry{
 		Config:    &TestConfig{},
@@ -98,7 +136,7 @@ func TestGetConfig(t *testing.T) {
 }
 
 func TestGetConfig_Nonexistent(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	config, ok := GetConfig("nonexistentConfig")
 	assert.False(t, ok, "expected false for nonexistent config")
@@ -106,7 +144,7 @@ func TestGetConfig_Nonexistent(t *testing.T) {
 }
 
 func TestBindActualValue_Valid(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	entry := ConfigEntry{
 		Config: &TestConfig{},
@@ -117,7 +155,7 @@ func TestBindActualValue_Valid(t *testing.T) {
 	}
 	RegisterConfig("testConfig", entry)
 
-	err := newConfigWithLock()
+	err := defaultRegistry.newConfigWithLock()
 	assert.NoError(t, err, "expected no error when binding actual values")
 
 	config, ok := GetConfig("testConfig")
@@ -130,7 +168,7 @@ func TestBindActualValue_Valid(t *testing.T) {
 }
 
 func TestBindActualValue_MissingField(t *testing.T) {
-	defer resetConfigEntries()
+	defer Reset()
 
 	entry := ConfigEntry{
 		Config: &TestConfig{},
@@ -155,9 +193,138 @@ func TestWithSetEnvPrefix_Empty(t *testing.T) {
 }
 
 func TestNewConfig_MultipleOptions(t *testing.T) {
-	err := NewConfig(WithSetEnvPrefix("test"), func() error {
-		viper.Set("customOption", true)
+	err := NewConfig(WithSetEnvPrefix("test"), func(r *Registry) error {
+		r.Viper().Set("customOption", true)
 		return nil
 	})
 	assert.NoError(t, err, "expected no error for valid functional options")
 }
+
+// resetViper clears the global viper state before and after the test.
+func resetViper(t *testing.T) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+}
+
+// writeConfigFile writes content to a file named name in a temporary directory and returns its path.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func registerTestConfig(t *testing.T) *TestConfig {
+	t.Helper()
+	conf := &TestConfig{}
+	err := RegisterConfig("testConfig", ConfigEntry{
+		Config: conf,
+		BindArray: []BindValue{
+			{ValName: "field1", DefaultVal: "default1"},
+			{ValName: "field2", DefaultVal: 123},
+		},
+	})
+	require.NoError(t, err)
+	return conf
+}
+
+func TestUnregisterConfig(t *testing.T) {
+	defer Reset()
+	registerTestConfig(t)
+
+	require.NoError(t, UnregisterConfig("testConfig"))
+	_, ok := GetConfig("testConfig")
+	assert.False(t, ok, "expected config to be removed")
+	assert.Empty(t, ListConfigs(), "expected no configs to be listed")
+
+	err := UnregisterConfig("testConfig")
+	assert.Error(t, err, "expected error for unregistered config")
+	assert.Contains(t, err.Error(), "is not registered")
+}
+
+func TestReset(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	registerTestConfig(t)
+	require.NoError(t, NewConfig())
+	assert.Nil(t, viper.Get("field1"), "expected entry defaults not to leak into the global viper instance")
+
+	Reset()
+
+	assert.Empty(t, ListConfigs(), "expected registry to be empty")
+
+	conf := registerTestConfig(t)
+	require.NoError(t, NewConfig())
+	assert.Equal(t, "default1", conf.Field1, "expected config to be registered again after Reset")
+}
+
+func TestReset_DropsSettings(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("HM_FIELD1", "prefixed")
+	path := writeConfigFile(t, "config.yaml", "field2: 7\n")
+	registerTestConfig(t)
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithConfigFile(path, "")))
+
+	Reset()
+	require.NoError(t, os.Remove(path))
+
+	conf := registerTestConfig(t)
+	require.NoError(t, NewConfig(), "expected Reset to drop the deleted config file")
+	assert.Equal(t, "default1", conf.Field1, "expected Reset to drop the environment prefix")
+	assert.Equal(t, 123, conf.Field2, "expected Reset to drop the config file values")
+}
+
+func TestUnregisterConfig_Concurrent(t *testing.T) {
+	defer Reset()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("config%d", i)
+			assert.NoError(t, RegisterConfig(name, ConfigEntry{Config: &TestConfig{}}))
+			_, _ = GetConfig(name)
+			assert.NoError(t, UnregisterConfig(name))
+		}(i)
+	}
+	wg.Wait()
+	assert.Empty(t, ListConfigs())
+}
+
+type OtherConfig struct {
+	Field3 bool `mapstructure:"field3"`
+}
+
+func TestGet(t *testing.T) {
+	defer Reset()
+	registered := registerTestConfig(t)
+
+	conf, err := Get[TestConfig]("testConfig")
+	require.NoError(t, err, "expected no error for matching type")
+	assert.Same(t, registered, conf, "expected the registered pointer to be returned")
+}
+
+func TestGet_WrongType(t *testing.T) {
+	defer Reset()
+	registerTestConfig(t)
+
+	conf, err := Get[OtherConfig]("testConfig")
+	require.Error(t, err, "expected error for mismatched type")
+	assert.Nil(t, conf)
+	assert.EqualError(t, err, "config 'testConfig' is *cfg.TestConfig, not *cfg.OtherConfig")
+}
+
+func TestGet_Missing(t *testing.T) {
+	defer Reset()
+
+	conf, err := Get[TestConfig]("nonexistentConfig")
+	require.Error(t, err, "expected error for missing config")
+	assert.Nil(t, conf)
+	assert.EqualError(t, err, "config 'nonexistentConfig' is not registered")
+}
+
+type LevelConfig struct {
+	Level string `mapstructure:"log_level"`
+}
@@ -0,0 +1,82 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithConfigFile_FileOnly(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerTestConfig(t)
+
+	path := writeConfigFile(t, "config.yaml", "field1: from_file\nfield2: 7\n")
+	require.NoError(t, NewConfig(WithConfigFile(path, "yaml")))
+
+	assert.Equal(t, "from_file", conf.Field1, "expected file value to override the default")
+	assert.Equal(t, 7, conf.Field2, "expected file value to override the default")
+}
+
+func TestWithConfigFile_EnvOnly(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerTestConfig(t)
+	t.Setenv("FIELD1", "from_env")
+
+	require.NoError(t, NewConfig())
+
+	assert.Equal(t, "from_env", conf.Field1, "expected env value to override the default")
+	assert.Equal(t, 123, conf.Field2, "expected default value for Field2")
+}
+
+func TestWithConfigFile_EnvOverridesFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		format  string
+	}{
+		{"yaml", "config.yaml", "field1: from_file\nfield2: 7\n", "yaml"},
+		{"json", "config.json", `{"field1": "from_file", "field2": 7}`, "json"},
+		{"toml inferred", "config.toml", "field1 = \"from_file\"\nfield2 = 7\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer Reset()
+			resetViper(t)
+			conf := registerTestConfig(t)
+			t.Setenv("FIELD1", "from_env")
+
+			path := writeConfigFile(t, tt.file, tt.content)
+			require.NoError(t, NewConfig(WithConfigFile(path, tt.format)))
+
+			assert.Equal(t, "from_env", conf.Field1, "expected env value to win over the file")
+			assert.Equal(t, 7, conf.Field2, "expected file value where no env is set")
+		})
+	}
+}
+
+func TestWithConfigFile_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		format string
+		msg    string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.yaml"), "yaml", "failed to read config file"},
+		{"empty path", "", "yaml", "config file path cannot be empty"},
+		{"unsupported format", "config.ini", "", "unsupported config file format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetViper(t)
+			err := NewConfig(WithConfigFile(tt.path, tt.format))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
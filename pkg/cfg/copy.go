@@ -0,0 +1,87 @@
+package cfg
+
+import "reflect"
+
+// GetConfigCopy retrieves a deep copy of a registered configuration struct by name.
+// The copy shares no pointers, slices or maps with the stored struct, so callers may
+// mutate it freely while NewConfig, Watch or SetValue update the original.
+// GetConfig remains the fast path returning the shared pointer.
+//
+// Example usage:
+//
+//	conf, ok := cfg.GetConfigCopy("http")
+//	if !ok {
+//		// Handle missing config
+//	}
+//	httpConf := conf.(*HttpConfig)
+func GetConfigCopy(name string) (any, bool) {
+	return defaultRegistry.GetCopy(name)
+}
+
+// GetCopy retrieves a deep copy of a configuration struct registered in r. See GetConfigCopy.
+func (r *Registry) GetCopy(name string) (any, bool) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	entry, ok := r.entries[name]
+	if !ok {
+		return nil, false
+	}
+	return deepCopy(reflect.ValueOf(entry.Config)).Interface(), true
+}
+
+// deepCopy returns a copy of v sharing no pointers, slices or maps with it.
+// Unexported struct fields are copied shallowly, as reflection cannot set them.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,89 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type CopiedConfig struct {
+	Proxies []string          `mapstructure:"trusted_proxies"`
+	Labels  map[string]string `mapstructure:"labels"`
+	Timeout time.Duration     `mapstructure:"net_timeout"`
+	Nested  struct {
+		Hosts []string
+	} `mapstructure:"nested"`
+	Inner  *LevelConfig `mapstructure:"inner"`
+	Extra  any          `mapstructure:"extra"`
+	hidden []int
+}
+
+func TestGetConfigCopy(t *testing.T) {
+	defer Reset()
+	orig := &CopiedConfig{
+		Proxies: []string{"10.0.0.1"},
+		Labels:  map[string]string{"env": "prod"},
+		Timeout: time.Second,
+		Inner:   &LevelConfig{Level: "info"},
+		Extra:   []int{1},
+		hidden:  []int{7},
+	}
+	orig.Nested.Hosts = []string{"a"}
+	require.NoError(t, RegisterConfig("net", ConfigEntry{Config: orig}))
+
+	got, ok := GetConfigCopy("net")
+	require.True(t, ok)
+	c := got.(*CopiedConfig)
+	require.NotSame(t, orig, c)
+	assert.Equal(t, orig, c)
+
+	c.Proxies[0] = "changed"
+	c.Labels["env"] = "changed"
+	c.Nested.Hosts[0] = "changed"
+	c.Inner.Level = "changed"
+	c.Extra.([]int)[0] = 2
+	assert.Equal(t, []string{"10.0.0.1"}, orig.Proxies)
+	assert.Equal(t, map[string]string{"env": "prod"}, orig.Labels)
+	assert.Equal(t, []string{"a"}, orig.Nested.Hosts)
+	assert.Equal(t, "info", orig.Inner.Level)
+	assert.Equal(t, []int{1}, orig.Extra)
+	assert.Equal(t, []int{7}, c.hidden, "expected unexported fields to be copied shallowly")
+
+	_, ok = GetConfigCopy("missing")
+	assert.False(t, ok)
+}
+
+func TestGetConfigCopy_ConcurrentNewConfig(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.1,10.0.0.2")
+	require.NoError(t, RegisterConfig("net", ConfigEntry{
+		Config:    &CopiedConfig{},
+		BindArray: []BindValue{{ValName: "trusted_proxies"}, {ValName: "net_timeout", DefaultVal: "1s"}},
+	}))
+	require.NoError(t, NewConfig())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, NewConfig())
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		got, ok := GetConfigCopy("net")
+		require.True(t, ok)
+		c := got.(*CopiedConfig)
+		c.Proxies[0] = "mutated"
+		c.Timeout = 0
+	}
+	wg.Wait()
+
+	conf, err := Get[CopiedConfig]("net")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, conf.Proxies)
+	assert.Equal(t, time.Second, conf.Timeout)
+}
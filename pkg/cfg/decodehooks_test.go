@@ -0,0 +1,68 @@
+package cfg
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type NetConfig struct {
+	TrustedProxies []string      `mapstructure:"trusted_proxies"`
+	Timeout        time.Duration `mapstructure:"net_timeout"`
+	BindIP         net.IP        `mapstructure:"bind_ip"`
+	Mode           netMode       `mapstructure:"net_mode"`
+}
+
+type netMode int
+
+func TestNewConfig_DecodeHooks(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("HM_TRUSTED_PROXIES", "10.0.0.1,10.0.0.2")
+	t.Setenv("HM_NET_TIMEOUT", "250ms")
+	t.Setenv("HM_BIND_IP", "192.168.1.10")
+	conf := &NetConfig{}
+	require.NoError(t, RegisterConfig("net", ConfigEntry{
+		Config: conf,
+		BindArray: []BindValue{
+			{ValName: "trusted_proxies"},
+			{ValName: "net_timeout"},
+			{ValName: "bind_ip"},
+		},
+	}))
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, conf.TrustedProxies)
+	assert.Equal(t, 250*time.Millisecond, conf.Timeout)
+	assert.True(t, net.ParseIP("192.168.1.10").Equal(conf.BindIP), "expected bind_ip to be parsed, got %v", conf.BindIP)
+}
+
+func TestWithDecodeHook(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("NET_MODE", "passive")
+	conf := &NetConfig{}
+	require.NoError(t, RegisterConfig("net", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "net_mode"}},
+	}))
+	modeHook := func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(netMode(0)) {
+			return data, nil
+		}
+		if data.(string) == "passive" {
+			return netMode(2), nil
+		}
+		return nil, fmt.Errorf("unknown mode %q", data)
+	}
+
+	require.Error(t, NewConfig(), "expected the custom value to fail without the hook")
+	require.NoError(t, NewConfig(WithDecodeHook(modeHook)))
+	assert.Equal(t, netMode(2), conf.Mode)
+
+	assert.ErrorContains(t, NewConfig(WithDecodeHook(nil)), "decode hook cannot be nil")
+}
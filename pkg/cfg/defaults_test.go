@@ -0,0 +1,129 @@
+package cfg
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRegisterConfig_DefaultTypes(t *testing.T) {
+	type typedConfig struct {
+		Count   int           `mapstructure:"count"`
+		Enabled bool          `mapstructure:"enabled"`
+		Timeout time.Duration `mapstructure:"timeout"`
+		Name    string        `mapstructure:"name"`
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		def     any
+		wantErr string
+	}{
+		{name: "int", key: "count", def: 3},
+		{name: "int from numeric string", key: "count", def: "123"},
+		{name: "int from invalid string", key: "count", def: "abc",
+			wantErr: "default of count is string, cannot convert to int (field typedConfig.Count)"},
+		{name: "int from bool", key: "count", def: true,
+			wantErr: "default of count is bool, cannot convert to int"},
+		{name: "bool", key: "enabled", def: true},
+		{name: "bool from string", key: "enabled", def: "true"},
+		{name: "bool from int", key: "enabled", def: 1,
+			wantErr: "default of enabled is int, cannot convert to bool"},
+		{name: "bool from invalid string", key: "enabled", def: "yes please",
+			wantErr: "default of enabled is string, cannot convert to bool"},
+		{name: "duration", key: "timeout", def: time.Second},
+		{name: "duration from string", key: "timeout", def: "250ms"},
+		{name: "duration from invalid string", key: "timeout", def: "5 seconds",
+			wantErr: "default of timeout is string, cannot convert to time.Duration"},
+		{name: "duration from bool", key: "timeout", def: false,
+			wantErr: "default of timeout is bool, cannot convert to time.Duration"},
+		{name: "string", key: "name", def: "svc"},
+		{name: "string from int", key: "name", def: 8080},
+		{name: "string from slice", key: "name", def: []string{"a"},
+			wantErr: "default of name is []string, cannot convert to string"},
+		{name: "string from struct", key: "name", def: struct{}{},
+			wantErr: "default of name is struct {}, cannot convert to string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer Reset()
+			err := RegisterConfig("typed", ConfigEntry{
+				Config:    &typedConfig{},
+				BindArray: []BindValue{{ValName: tt.key, DefaultVal: tt.def}},
+			})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var entryErr *EntryError
+			require.ErrorAs(t, err, &entryErr)
+			assert.Equal(t, "typed", entryErr.Name)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+type WorkersConfig struct {
+	Workers int `mapstructure:"workers"`
+}
+
+func TestBindValue_DefaultFunc(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := &WorkersConfig{}
+	require.NoError(t, RegisterConfig("pool", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "workers", DefaultFunc: func() any { return runtime.NumCPU() }}},
+	}))
+
+	require.NoError(t, NewConfig())
+	assert.Equal(t, runtime.NumCPU(), conf.Workers)
+
+	t.Setenv("WORKERS", "3")
+	require.NoError(t, NewConfig())
+	assert.Equal(t, 3, conf.Workers, "expected the environment to win over the computed default")
+}
+
+func TestBindValue_DefaultFuncErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() any
+		wantErr string
+	}{
+		{name: "error", fn: func() any { return errors.New("no hostname") },
+			wantErr: "failed to compute default of workers: no hostname"},
+		{name: "panic", fn: func() any { panic("boom") },
+			wantErr: "failed to compute default of workers: panic: boom"},
+		{name: "wrong type", fn: func() any { return "abc" },
+			wantErr: "DefaultFunc returned an invalid default: default of workers is string, cannot convert to int (field WorkersConfig.Workers)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer Reset()
+			resetViper(t)
+			require.NoError(t, RegisterConfig("pool", ConfigEntry{
+				Config:    &WorkersConfig{},
+				BindArray: []BindValue{{ValName: "workers", DefaultFunc: tt.fn}},
+			}))
+
+			err := NewConfig()
+			var cfgErrs ConfigErrors
+			require.ErrorAs(t, err, &cfgErrs)
+			assert.Equal(t, []string{"pool"}, cfgErrs.Names())
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBindValue_DefaultFuncWithDefaultVal(t *testing.T) {
+	defer Reset()
+	err := RegisterConfig("pool", ConfigEntry{
+		Config:    &WorkersConfig{},
+		BindArray: []BindValue{{ValName: "workers", DefaultVal: 4, DefaultFunc: func() any { return 8 }}},
+	})
+	assert.ErrorContains(t, err, "BindValue workers cannot set both DefaultVal and DefaultFunc")
+}
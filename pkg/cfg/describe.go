@@ -0,0 +1,184 @@
+package cfg
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// VarDoc documents one environment variable read by a registered config.
+type VarDoc struct {
+	Entry    string // Registered config name
+	EnvVar   string // Environment variable name with the prefix applied
+	Key      string // BindValue.ValName
+	Field    string // Go field the value maps to, e.g. "LoggingConfig.Level"; empty if no field matches
+	Default  any    // BindValue.DefaultVal; nil if Computed
+	Computed bool   // BindValue.DefaultFunc is set, so the default is computed at load time
+	Validate string // validate tag of the field
+	Required bool   // BindValue.Required
+}
+
+// Describe returns a VarDoc for every BindValue of the registered configs, ordered by config name
+// and then by binding order. Fields of squashed and nested structs are resolved as viper would.
+// The global prefix is the one set by the last WithSetEnvPrefix.
+//
+// Example usage:
+//
+//	for _, doc := range cfg.Describe() {
+//		fmt.Printf("%s -> %s (default %v)\n", doc.EnvVar, doc.Field, doc.Default)
+//	}
+func Describe() []VarDoc {
+	return defaultRegistry.Describe()
+}
+
+// Describe returns a VarDoc for every BindValue of the configs registered in r. See the package-level Describe.
+func (r *Registry) Describe() []VarDoc {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	var docs []VarDoc
+	for _, name := range r.sortedEntryNames() {
+		entry := r.entries[name]
+		t := reflect.TypeOf(entry.Config).Elem()
+		fields := fieldsByKey(t)
+		prefix := r.entryEnvPrefix(&entry)
+		for _, b := range entry.BindArray {
+			f := fields[strings.ToLower(b.ValName)]
+			docs = append(docs, VarDoc{
+				Entry:    name,
+				EnvVar:   envName(prefix, b.ValName),
+				Key:      b.ValName,
+				Field:    f.path,
+				Default:  b.DefaultVal,
+				Computed: b.DefaultFunc != nil,
+				Validate: f.validate,
+				Required: b.Required,
+			})
+		}
+	}
+	return docs
+}
+
+// WriteEnvExample writes a .env template of every variable returned by Describe to w.
+// Variables with a default are commented out with their default value, those with a computed
+// default are commented out and marked as computed, the others are left empty.
+//
+// Example usage:
+//
+//	f, err := os.Create(".env.example")
+//	if err != nil {
+//		// Handle error
+//	}
+//	defer f.Close()
+//	err = cfg.WriteEnvExample(f)
+func WriteEnvExample(w io.Writer) error {
+	return defaultRegistry.WriteEnvExample(w)
+}
+
+// WriteEnvExample writes a .env template of the configs registered in r. See the package-level WriteEnvExample.
+func (r *Registry) WriteEnvExample(w io.Writer) error {
+	var b strings.Builder
+	entry := ""
+	for _, doc := range r.Describe() {
+		if doc.Entry != entry {
+			if entry != "" {
+				b.WriteString("\n")
+			}
+			entry = doc.Entry
+			fmt.Fprintf(&b, "# %s\n", entry)
+		}
+
+		comment := doc.Field
+		if comment == "" {
+			comment = doc.Key
+		}
+		if doc.Validate != "" {
+			comment += " (validate: " + doc.Validate + ")"
+		}
+		if doc.Required {
+			comment += " [required]"
+		}
+		if doc.Computed {
+			comment += " [default computed at load time]"
+		}
+		fmt.Fprintf(&b, "# %s\n", comment)
+
+		switch {
+		case isNotNullOrDefault(doc.Default):
+			fmt.Fprintf(&b, "# %s=%v\n", doc.EnvVar, doc.Default)
+		case doc.Computed:
+			fmt.Fprintf(&b, "# %s=\n", doc.EnvVar)
+		default:
+			fmt.Fprintf(&b, "%s=\n", doc.EnvVar)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fieldDoc is the Go field path, type and validate tag of a mapstructure key.
+type fieldDoc struct {
+	key      string
+	path     string
+	index    []int // Index sequence of the field for reflect.Value.FieldByIndex
+	typ      reflect.Type
+	validate string
+}
+
+// fieldsByKey maps the lowercased mapstructure keys of the struct type t to their fields.
+func fieldsByKey(t reflect.Type) map[string]fieldDoc {
+	fields := make(map[string]fieldDoc)
+	for _, f := range collectFields(t, t.Name(), "", nil, nil) {
+		fields[f.key] = f
+	}
+	return fields
+}
+
+// collectFields appends the leaf fields of t to out in declaration order, keyed by their
+// lowercased mapstructure keys prefixed with keyPrefix and indexed from index. Squashed structs
+// share the key space of their parent, other nested structs add their own key as a dotted prefix.
+// time.Time and other structs of package time are leaves.
+func collectFields(t reflect.Type, path, keyPrefix string, index []int, out []fieldDoc) []fieldDoc {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if key == "-" {
+			continue
+		}
+		fieldPath := path + "." + field.Name
+		fieldIndex := append(slices.Clone(index), i)
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft.PkgPath() != "time" {
+			if strings.Contains(opts, "squash") {
+				out = collectFields(ft, fieldPath, keyPrefix, fieldIndex, out)
+				continue
+			}
+			if key == "" {
+				key = field.Name
+			}
+			out = collectFields(ft, fieldPath, keyPrefix+strings.ToLower(key)+".", fieldIndex, out)
+			continue
+		}
+
+		if key == "" {
+			key = field.Name
+		}
+		out = append(out, fieldDoc{
+			key:      keyPrefix + strings.ToLower(key),
+			path:     fieldPath,
+			index:    fieldIndex,
+			typ:      field.Type,
+			validate: field.Tag.Get("validate"),
+		})
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package cfg
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type DescribedBase struct {
+	Level string `mapstructure:"log_level" validate:"oneof=debug info warn error"`
+}
+
+type DescribedConfig struct {
+	DescribedBase `mapstructure:",squash"`
+	Timeout       time.Duration `mapstructure:"net_timeout" validate:"min=100ms"`
+	DB            struct {
+		URI string `mapstructure:"uri" validate:"required"`
+	} `mapstructure:"db"`
+}
+
+func TestDescribe(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("svc", ConfigEntry{
+		Config: &DescribedConfig{},
+		BindArray: []BindValue{
+			{ValName: "log_level", DefaultVal: "info"},
+			{ValName: "net_timeout", DefaultVal: time.Second},
+			{ValName: "db.uri"},
+			{ValName: "unmapped", DefaultFunc: func() any { return "computed" }},
+		},
+	}))
+	require.NoError(t, RegisterConfig("aux", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level"}},
+		EnvPrefix: "AUX",
+	}))
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+
+	assert.Equal(t, []VarDoc{
+		{Entry: "aux", EnvVar: "AUX_LOG_LEVEL", Key: "log_level", Field: "LevelConfig.Level"},
+		{Entry: "svc", EnvVar: "HM_LOG_LEVEL", Key: "log_level", Field: "DescribedConfig.DescribedBase.Level",
+			Default: "info", Validate: "oneof=debug info warn error"},
+		{Entry: "svc", EnvVar: "HM_NET_TIMEOUT", Key: "net_timeout", Field: "DescribedConfig.Timeout",
+			Default: time.Second, Validate: "min=100ms"},
+		{Entry: "svc", EnvVar: "HM_DB_URI", Key: "db.uri", Field: "DescribedConfig.DB.URI", Validate: "required"},
+		{Entry: "svc", EnvVar: "HM_UNMAPPED", Key: "unmapped", Computed: true},
+	}, Describe())
+}
+
+func TestWriteEnvExample(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config: &DescribedConfig{},
+		BindArray: []BindValue{
+			{ValName: "log_level", DefaultVal: "info"},
+			{ValName: "db.uri", Required: true},
+			{ValName: "net_timeout", DefaultFunc: func() any { return time.Second }},
+		},
+	}))
+	require.NoError(t, RegisterConfig("other", ConfigEntry{
+		Config:    &OtherConfig{},
+		BindArray: []BindValue{{ValName: "field3"}},
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteEnvExample(&buf))
+	assert.Equal(t, `# log
+# DescribedConfig.DescribedBase.Level (validate: oneof=debug info warn error)
+# LOG_LEVEL=info
+# DescribedConfig.DB.URI (validate: required) [required]
+DB_URI=
+# DescribedConfig.Timeout (validate: min=100ms) [default computed at load time]
+# NET_TIMEOUT=
+
+# other
+# OtherConfig.Field3
+FIELD3=
+`, buf.String())
+}
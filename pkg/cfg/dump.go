@@ -0,0 +1,132 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// redactedValue replaces the values of secret fields in DumpConfigs.
+const redactedValue = "***"
+
+// secretNameHints are the field name words treated as secrets even without a secret tag.
+var secretNameHints = []string{"password", "secret", "token", "uri"}
+
+// DumpConfigs returns the current values of all registered configs keyed by config name
+// and then by lowercased mapstructure key, resolving fields like Describe and viper do:
+// squashed structs are flattened, nested structs become nested maps and time.Time fields
+// are values. Fields tagged `secret:"true"`, or whose name or key has a word password, secret,
+// token or uri, are masked as "***": "db_uri" and "ApiToken" are, "security_level" is not.
+// Words are separated by '_', '.', '-' and camel case. The result is safe to log, and slog
+// prints map keys in sorted order.
+//
+// Example usage:
+//
+//	dump, err := cfg.DumpConfigs()
+//	if err != nil {
+//		// Handle error
+//	}
+//	logger.Info("effective configuration", "config", dump)
+func DumpConfigs() (map[string]map[string]any, error) {
+	return defaultRegistry.Dump()
+}
+
+// Dump returns the current values of all configs registered in r. See DumpConfigs.
+func (r *Registry) Dump() (map[string]map[string]any, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	dump := make(map[string]map[string]any, len(r.entries))
+	for name, entry := range r.entries {
+		v := reflect.ValueOf(entry.Config)
+		if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("config '%s' is not a non-nil pointer to a struct", name)
+		}
+		dump[name] = dumpStruct(v.Elem(), redact)
+	}
+	return dump, nil
+}
+
+// redact replaces the value of a secret field in DumpConfigs.
+func redact(reflect.Value) any {
+	return redactedValue
+}
+
+// dumpStruct converts the fields of v, as resolved by collectFields, into a map keyed by
+// mapstructure key with nested structs as nested maps, replacing the values of secret fields
+// by secret(value). Values are deep copies; fields beneath a nil struct pointer are nil.
+func dumpStruct(v reflect.Value, secret func(reflect.Value) any) map[string]any {
+	out := make(map[string]any)
+	for _, f := range collectFields(v.Type(), v.Type().Name(), "", nil, nil) {
+		keys := strings.Split(f.key, ".")
+		section := out
+		for _, key := range keys[:len(keys)-1] {
+			sub, ok := section[key].(map[string]any)
+			if !ok {
+				sub = make(map[string]any)
+				section[key] = sub
+			}
+			section = sub
+		}
+		key := keys[len(keys)-1]
+
+		fv, err := v.FieldByIndexErr(f.index)
+		switch {
+		case err != nil:
+			section[key] = nil
+		case isSecretPath(v.Type(), f.index):
+			section[key] = secret(fv)
+		default:
+			section[key] = deepCopy(fv).Interface()
+		}
+	}
+	return out
+}
+
+// isSecretPath reports whether the field of t at index, or one of the structs containing it, is a secret field.
+func isSecretPath(t reflect.Type, index []int) bool {
+	for i := range index {
+		field := t.FieldByIndex(index[:i+1])
+		key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if isSecretField(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretField reports whether field is tagged as a secret or named like one.
+func isSecretField(field reflect.StructField, key string) bool {
+	if field.Tag.Get("secret") == "true" {
+		return true
+	}
+	words := append(nameWords(field.Name), nameWords(key)...)
+	for _, hint := range secretNameHints {
+		if slices.Contains(words, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// nameWords splits name into lower case words at '_', '.' and '-' and at camel case
+// boundaries, e.g. "DBUri" into "db" and "uri".
+func nameWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '.' || r == '-' }) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerBefore := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			acronymEnd := unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsUpper(runes[i]) && (lowerBefore || acronymEnd) {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+	return words
+}
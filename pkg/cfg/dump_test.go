@@ -0,0 +1,126 @@
+package cfg
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type DumpConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	ApiKey   string `mapstructure:"api_key" secret:"true"`
+	Password string `mapstructure:"password"`
+	DbUri    string `mapstructure:"db_uri"`
+	Nested   struct {
+		Enabled bool   `mapstructure:"enabled"`
+		Token   string `mapstructure:"token"`
+	} `mapstructure:"nested"`
+	internal string
+}
+
+func TestDumpConfigs(t *testing.T) {
+	defer Reset()
+	conf := &DumpConfig{Host: "localhost", Port: 8080, ApiKey: "key", Password: "pass", DbUri: "postgres://u:p@db"}
+	conf.Nested.Enabled = true
+	conf.Nested.Token = "tok"
+	conf.internal = "hidden"
+	require.NoError(t, RegisterConfig("dump", ConfigEntry{Config: conf}))
+
+	dump, err := DumpConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]any{
+		"dump": {
+			"host":     "localhost",
+			"port":     8080,
+			"api_key":  "***",
+			"password": "***",
+			"db_uri":   "***",
+			"nested": map[string]any{
+				"enabled": true,
+				"token":   "***",
+			},
+		},
+	}, dump)
+}
+
+// TestDumpConfigs_SecretWords ensures that name hints match whole words of the field name or key,
+// so keys merely containing a hint, like "security_level" containing "uri", are not masked.
+func TestDumpConfigs_SecretWords(t *testing.T) {
+	defer Reset()
+	conf := &struct {
+		SecurityLevel string `mapstructure:"security_level"`
+		Security      string `mapstructure:"security"`
+		Tokens        int    `mapstructure:"max-tokens"`
+		APIToken      string
+		DBUri         string
+		Upstream      string `mapstructure:"upstream.uri"`
+	}{SecurityLevel: "high", Security: "strict", Tokens: 10, APIToken: "tok", DBUri: "postgres://u:p@db", Upstream: "http://u:p@up"}
+	require.NoError(t, RegisterConfig("words", ConfigEntry{Config: conf}))
+
+	dump, err := DumpConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"security_level": "high",
+		"security":       "strict",
+		"max-tokens":     10,
+		"apitoken":       "***",
+		"dburi":          "***",
+		"upstream":       map[string]any{"uri": "***"},
+	}, dump["words"])
+}
+
+type DumpBase struct {
+	Host     string `mapstructure:"host"`
+	Password string `mapstructure:"password"`
+}
+
+// TestDumpConfigs_SquashAndTime ensures that squashed structs are flattened into their parent
+// and time.Time fields are dumped as values, as viper decodes them.
+func TestDumpConfigs_SquashAndTime(t *testing.T) {
+	defer Reset()
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	conf := &struct {
+		DumpBase `mapstructure:",squash"`
+		Started  time.Time `mapstructure:"started"`
+		Limits   *struct {
+			Max int `mapstructure:"max"`
+		} `mapstructure:"limits"`
+	}{DumpBase: DumpBase{Host: "localhost", Password: "pass"}, Started: started}
+	require.NoError(t, RegisterConfig("squash", ConfigEntry{Config: conf}))
+
+	dump, err := DumpConfigs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"host":     "localhost",
+		"password": "***",
+		"started":  started,
+		"limits":   map[string]any{"max": nil},
+	}, dump["squash"])
+}
+
+func TestDumpConfigs_SafeToLog(t *testing.T) {
+	defer Reset()
+	require.NoError(t, RegisterConfig("dump", ConfigEntry{Config: &DumpConfig{Host: "b", Password: "hunter2"}}))
+	require.NoError(t, RegisterConfig("another", ConfigEntry{Config: &TestConfig{Field1: "a"}}))
+
+	dump, err := DumpConfigs()
+	require.NoError(t, err)
+
+	// drop the timestamp so repeated records are comparable
+	opts := &slog.HandlerOptions{ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return a
+	}}
+	var first, second bytes.Buffer
+	slog.New(slog.NewJSONHandler(&first, opts)).Info("config", "config", dump)
+	slog.New(slog.NewJSONHandler(&second, opts)).Info("config", "config", dump)
+	assert.NotContains(t, first.String(), "hunter2")
+	assert.Contains(t, first.String(), `"config":{"another":{"field1":"a","field2":0},"dump":{"api_key":"***"`)
+	assert.Equal(t, first.String(), second.String(), "expected a stable output")
+}
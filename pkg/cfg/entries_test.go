@@ -0,0 +1,30 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestListEntries(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	assert.Empty(t, ListEntries())
+
+	t.Setenv("FIELD2", "not a number")
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level"}},
+	}))
+	require.NoError(t, RegisterConfig("http", ConfigEntry{
+		Config:    &TestConfig{},
+		BindArray: []BindValue{{ValName: "field1"}, {ValName: "field2"}},
+	}))
+	require.Error(t, NewConfig(), "expected http to fail to load")
+
+	assert.Equal(t, []EntryInfo{
+		{Name: "http", Type: "cfg.TestConfig", BindValues: 2, Loaded: false},
+		{Name: "log", Type: "cfg.LevelConfig", BindValues: 1, Loaded: true},
+	}, ListEntries())
+	assert.ElementsMatch(t, []string{"http", "log"}, ListConfigs(), "expected ListConfigs to be unchanged")
+}
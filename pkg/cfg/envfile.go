@@ -0,0 +1,154 @@
+package cfg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WithEnvFile makes NewConfig read variables from a dotenv file at path, as if they were set
+// in the environment: lines of KEY=VALUE with optional "export " prefixes, "#" comments,
+// and single or double quoted values. Variables actually set in the environment win over
+// the file, and the file wins over config file values and defaults.
+// If the file is missing, NewConfig fails unless optional is true.
+//
+// Example Usage:
+//
+//	err := cfg.NewConfig(cfg.WithSetEnvPrefix("HM"), cfg.WithEnvFile(".env", true))
+//	if err != nil {
+//	    fmt.Printf("Error initializing configs: %v\n", err)
+//	}
+func WithEnvFile(path string, optional bool) ViperOption {
+	return func(r *Registry) error {
+		f, err := os.Open(path)
+		if err != nil {
+			if optional && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return fmt.Errorf("failed to read env file %s: %w", path, err)
+		}
+		defer func() { _ = f.Close() }()
+
+		vars, err := parseEnvFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to parse env file %s: %w", path, err)
+		}
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		if r.envFile == nil {
+			r.envFile = make(map[string]string, len(vars))
+		}
+		for k, v := range vars {
+			r.envFile[k] = v
+		}
+		return nil
+	}
+}
+
+// parseEnvFile parses dotenv content into a map of variables.
+func parseEnvFile(rd io.Reader) (map[string]string, error) {
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(rd)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		value, err := parseEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// parseEnvValue unquotes a dotenv value. Double quoted values support Go escapes, single quoted
+// values are literal, and unquoted values end at a " #" comment.
+func parseEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	switch quote := value[0]; quote {
+	case '"', '\'':
+		end := closingQuote(value, quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated %c quote", quote)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after quoted value", rest)
+		}
+		if quote == '\'' {
+			return value[1:end], nil
+		}
+		return strconv.Unquote(value[:end+1])
+	default:
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
+
+// closingQuote returns the index of the quote closing value[0], skipping escaped double quotes, or -1.
+func closingQuote(value string, quote byte) int {
+	for i := 1; i < len(value); i++ {
+		switch {
+		case quote == '"' && value[i] == '\\':
+			i++
+		case value[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// bindEnvFile sets the entry bindings whose variable is read by WithEnvFile and is neither set
+// in the environment nor on the command line. The caller must hold mtx.
+func (r *Registry) bindEnvFile(v *viper.Viper, entry *ConfigEntry) {
+	if len(r.envFile) == 0 {
+		return
+	}
+	prefix := r.entryEnvPrefix(entry)
+	flags := r.flagSet.Load()
+	for _, e := range entry.BindArray {
+		if envSet(prefix, e) || (flags != nil && flags.Changed(flagName(e.ValName))) {
+			continue
+		}
+		if value, ok := r.envFileValue(prefix, e); ok {
+			v.Set(e.ValName, value)
+		}
+	}
+}
+
+// envFileSet reports whether the variable of e or one of its aliases is read by WithEnvFile.
+func (r *Registry) envFileSet(prefix string, e BindValue) bool {
+	_, ok := r.envFileValue(prefix, e)
+	return ok
+}
+
+// envFileValue returns the value read by WithEnvFile for the primary variable of e, or else its first alias.
+func (r *Registry) envFileValue(prefix string, e BindValue) (string, bool) {
+	for _, name := range append([]string{e.ValName}, e.Aliases...) {
+		if value, ok := r.envFile[envName(prefix, name)]; ok && value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,94 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	content := `# comment line
+
+HM_PLAIN=value
+HM_SPACED = spaced value  # trailing comment
+export HM_EXPORTED=exported
+HM_DOUBLE="quoted # not a comment"
+HM_ESCAPED="line1\nline2 \"q\""
+HM_SINGLE='literal \n $VAR'
+HM_EMPTY=
+HM_HASH=a#b
+`
+	vars, err := parseEnvFile(strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"HM_PLAIN":    "value",
+		"HM_SPACED":   "spaced value",
+		"HM_EXPORTED": "exported",
+		"HM_DOUBLE":   "quoted # not a comment",
+		"HM_ESCAPED":  "line1\nline2 \"q\"",
+		"HM_SINGLE":   `literal \n $VAR`,
+		"HM_EMPTY":    "",
+		"HM_HASH":     "a#b",
+	}, vars)
+}
+
+func TestParseEnvFile_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing separator":  "HM_KEY",
+		"empty key":          "=value",
+		"space in key":       "HM KEY=value",
+		"unterminated quote": `HM_KEY="value`,
+		"text after quotes":  `HM_KEY="value" extra`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseEnvFile(strings.NewReader("# header\n" + content))
+			assert.ErrorContains(t, err, "line 2:")
+		})
+	}
+}
+
+func TestWithEnvFile(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	path := writeConfigFile(t, ".env", "HM_LOG_LEVEL=debug\nexport HM_FIELD1=from-env-file\n")
+	t.Setenv("HM_FIELD1", "from-env")
+	level := &LevelConfig{}
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    level,
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+	}))
+	conf := registerTestConfig(t)
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithEnvFile(path, false)))
+	assert.Equal(t, "debug", level.Level, "expected the env file to win over defaults")
+	assert.Equal(t, "from-env", conf.Field1, "expected real env vars to win over the env file")
+
+	sources, err := Explain("log")
+	require.NoError(t, err)
+	assert.Equal(t, SourceEnvFile, sources["log_level"])
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+	assert.Equal(t, "info", level.Level, "expected env file values to apply only to loads using WithEnvFile")
+}
+
+func TestWithEnvFile_Missing(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	path := filepath.Join(t.TempDir(), ".env")
+
+	assert.ErrorContains(t, NewConfig(WithEnvFile(path, false)), "failed to read env file")
+	assert.NoError(t, NewConfig(WithEnvFile(path, true)), "expected a missing optional env file to be skipped")
+}
+
+func TestWithEnvFile_StrictEnv(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	path := writeConfigFile(t, ".env", "HM_LOG_LEVLE=debug\n")
+	registerStrictConfigs(t)
+
+	err := NewConfig(WithSetEnvPrefix("HM"), WithEnvFile(path, false), WithStrictEnv())
+	assert.EqualError(t, err, "unknown environment variables: HM_LOG_LEVLE")
+}
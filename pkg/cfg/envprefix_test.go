@@ -0,0 +1,51 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestConfigEntry_EnvPrefix(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	hm, aux := &LevelConfig{}, &LevelConfig{}
+	require.NoError(t, RegisterConfig("hm", ConfigEntry{
+		Config:    hm,
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+	}))
+	require.NoError(t, RegisterConfig("aux", ConfigEntry{
+		Config:    aux,
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+		EnvPrefix: "AUX",
+	}))
+	t.Setenv("HM_LOG_LEVEL", "debug")
+	t.Setenv("AUX_LOG_LEVEL", "warn")
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+
+	assert.Equal(t, "debug", hm.Level, "expected the global prefix for entries without EnvPrefix")
+	assert.Equal(t, "warn", aux.Level, "expected the entry prefix for entries with EnvPrefix")
+}
+
+func TestConfigEntry_EnvPrefixWithConfigFile(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	aux := &TestConfig{}
+	require.NoError(t, RegisterConfig("aux", ConfigEntry{
+		Config: aux,
+		BindArray: []BindValue{
+			{ValName: "field1", DefaultVal: "default1"},
+			{ValName: "field2", DefaultVal: 123},
+		},
+		EnvPrefix: "AUX",
+	}))
+	t.Setenv("AUX_FIELD1", "from_env")
+	t.Setenv("FIELD2", "1")
+
+	path := writeConfigFile(t, "config", "field2: 7\n")
+	require.NoError(t, NewConfig(WithConfigFile(path, "yaml")))
+
+	assert.Equal(t, "from_env", aux.Field1, "expected the prefixed env value")
+	assert.Equal(t, 7, aux.Field2, "expected the file value, not the unprefixed env value")
+}
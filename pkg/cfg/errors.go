@@ -0,0 +1,57 @@
+package cfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntryError attributes an error to the registered config it occurred in.
+type EntryError struct {
+	Name string // Registered config name
+	Err  error  // Underlying error
+}
+
+func (e *EntryError) Error() string {
+	return fmt.Sprintf("config '%s': %v", e.Name, e.Err)
+}
+
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors aggregates the errors of all failing entries of a NewConfig call,
+// ordered by config name.
+//
+// Example usage:
+//
+//	var cfgErrs cfg.ConfigErrors
+//	if errors.As(err, &cfgErrs) {
+//		fmt.Println("failing configs:", cfgErrs.Names())
+//	}
+type ConfigErrors []*EntryError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the entry errors, so errors.Is and errors.As inspect each of them.
+func (e ConfigErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Names returns the names of the failing configs.
+func (e ConfigErrors) Names() []string {
+	names := make([]string, 0, len(e))
+	for _, err := range e {
+		names = append(names, err.Name)
+	}
+	return names
+}
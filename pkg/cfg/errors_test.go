@@ -0,0 +1,40 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewConfig_AggregatesBindErrors(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("FIELD2", "not a number")
+	for _, name := range []string{"second", "first"} {
+		require.NoError(t, RegisterConfig(name, ConfigEntry{
+			Config:    &TestConfig{},
+			BindArray: []BindValue{{ValName: "field2"}},
+		}))
+	}
+	require.NoError(t, RegisterConfig("healthy", ConfigEntry{
+		Config:    &OtherConfig{},
+		BindArray: []BindValue{{ValName: "field3", DefaultVal: true}},
+	}))
+
+	err := NewConfig()
+	require.Error(t, err, "expected bind errors")
+
+	var cfgErrs ConfigErrors
+	require.ErrorAs(t, err, &cfgErrs)
+	assert.Equal(t, []string{"first", "second"}, cfgErrs.Names(), "expected every failing entry in name order")
+	assert.Contains(t, err.Error(), "config 'first': failed to bind: failed to unmarshal into config")
+	assert.Contains(t, err.Error(), "config 'second': failed to bind: failed to unmarshal into config")
+
+	var entryErr *EntryError
+	require.ErrorAs(t, err, &entryErr)
+	assert.Equal(t, "first", entryErr.Name)
+
+	healthy, err := Get[OtherConfig]("healthy")
+	require.NoError(t, err)
+	assert.True(t, healthy.Field3, "expected healthy entries to be loaded despite failures")
+}
@@ -0,0 +1,108 @@
+package cfg
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+type SecretConfig struct {
+	DbUri string `mapstructure:"db_uri"`
+	Token string `mapstructure:"token"`
+}
+
+func registerSecretConfig(t *testing.T) *SecretConfig {
+	t.Helper()
+	conf := &SecretConfig{}
+	require.NoError(t, RegisterConfig("secret", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "db_uri"}, {ValName: "token", DefaultVal: "default"}},
+	}))
+	return conf
+}
+
+func TestWithFileSecrets(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerSecretConfig(t)
+	t.Setenv("HM_DB_URI_FILE", writeConfigFile(t, "db_uri", "postgres://user:pass@db/herd\n"))
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithFileSecrets()))
+
+	assert.Equal(t, "postgres://user:pass@db/herd", conf.DbUri, "expected the trimmed file contents")
+	assert.Equal(t, "default", conf.Token, "expected bindings without _FILE to be untouched")
+	assert.Empty(t, Warnings())
+}
+
+func TestWithFileSecrets_PlainEnvWins(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerSecretConfig(t)
+	t.Setenv("HM_TOKEN", "from_env")
+	t.Setenv("HM_TOKEN_FILE", writeConfigFile(t, "token", "from_file\n"))
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithFileSecrets()))
+
+	assert.Equal(t, "from_env", conf.Token, "expected the plain variable to win")
+	warnings := Warnings()
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0].Error(), "both HM_TOKEN and HM_TOKEN_FILE are set")
+}
+
+func TestWithFileSecrets_EnvFileWins(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerSecretConfig(t)
+	path := writeConfigFile(t, ".env", "HM_TOKEN=from_env_file\n")
+	t.Setenv("HM_TOKEN_FILE", writeConfigFile(t, "token", "from_file\n"))
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithEnvFile(path, false), WithFileSecrets()))
+
+	assert.Equal(t, "from_env_file", conf.Token, "expected the plain variable of the env file to win")
+	sources, err := Explain("secret")
+	require.NoError(t, err)
+	assert.Equal(t, SourceEnvFile, sources["token"])
+	warnings := Warnings()
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0].Error(), "both HM_TOKEN and HM_TOKEN_FILE are set")
+}
+
+func TestWithFileSecrets_MissingFile(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	registerSecretConfig(t)
+	t.Setenv("HM_DB_URI_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	err := NewConfig(WithSetEnvPrefix("HM"), WithFileSecrets())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read secret file for db_uri from HM_DB_URI_FILE")
+}
+
+func TestWithFileSecrets_Disabled(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerSecretConfig(t)
+	t.Setenv("HM_DB_URI_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")), "expected _FILE variables to be ignored without WithFileSecrets")
+	assert.Empty(t, conf.DbUri)
+}
+
+func TestWithFileSecrets_FlagWins(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerSecretConfig(t)
+	t.Setenv("HM_DB_URI_FILE", writeConfigFile(t, "db_uri", "from_file\n"))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opt := WithFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--db-uri", "from_flag"}))
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithFileSecrets(), opt))
+
+	assert.Equal(t, "from_flag", conf.DbUri, "expected the flag to win over the _FILE secret")
+	sources, err := Explain("secret")
+	require.NoError(t, err)
+	assert.Equal(t, SourceFlag, sources["db_uri"])
+}
@@ -0,0 +1,77 @@
+package cfg
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func registerLevelConfig(t *testing.T) *LevelConfig {
+	t.Helper()
+	conf := &LevelConfig{}
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+	}))
+	return conf
+}
+
+func TestWithFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want string
+	}{
+		{"flag overrides env", []string{"--log-level", "debug"}, "warn", "debug"},
+		{"env overrides flag default", nil, "warn", "warn"},
+		{"flag default", nil, "", "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer Reset()
+			resetViper(t)
+			conf := registerLevelConfig(t)
+			if tt.env != "" {
+				t.Setenv("LOG_LEVEL", tt.env)
+			}
+
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			opt := WithFlags(fs)
+			flag := fs.Lookup("log-level")
+			require.NotNil(t, flag, "expected a flag to be declared for log_level")
+			assert.Equal(t, "info", flag.DefValue)
+			require.NoError(t, fs.Parse(tt.args))
+
+			require.NoError(t, NewConfig(opt))
+			assert.Equal(t, tt.want, conf.Level)
+		})
+	}
+}
+
+func TestWithFlags_Unparsed(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	registerLevelConfig(t)
+
+	err := NewConfig(WithFlags(pflag.NewFlagSet("test", pflag.ContinueOnError)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "flag set must be parsed before NewConfig")
+}
+
+func TestWithFlags_TypedDefaults(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerTestConfig(t)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opt := WithFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--field2=42"}))
+	require.NoError(t, NewConfig(opt))
+
+	assert.Equal(t, 42, conf.Field2)
+	assert.Equal(t, "default1", conf.Field1)
+	assert.Error(t, fs.Parse([]string{"--field2=abc"}), "expected an int flag for an int default")
+}
@@ -82,10 +82,10 @@ import "time"
 //   - Host: The IP address or hostname of the gRPC server.
 //     Validates as IPv4 or hostname (RFC1123).
 //   - Port: The port number for the gRPC server.
-//     Validates as a numeric value between 1025 and 65 535 (exclusive).
+//     Validates as an unprivileged port, 1025 to 65 535 (inclusive).
 type GrpcConfig struct {
-	Host string `mapstructure:"grpc_host" validate:"ip4_addr|hostname_rfc1123,required"`
-	Port int    `mapstructure:"grpc_port" validate:"numeric,gt=1024,lt=65536,required"`
+	Host string `mapstructure:"grpc_host" validate:"host_or_ip,required"`
+	Port int    `mapstructure:"grpc_port" validate:"svc_port,required"`
 }
 
 // LoggingConfig represents the configuration for logging systems.
@@ -95,8 +95,8 @@ type GrpcConfig struct {
 //   - Format: Specifies the log format, either "text" or "json".
 //   - Level: Specifies the log level, which must be one of "debug", "info", "warn", or "error".
 type LoggingConfig struct {
-	Format string `mapstructure:"log_format" validate:"oneof=text json"`
-	Level  string `mapstructure:"log_level" validate:"oneof=debug info warn error"`
+	Format string `mapstructure:"log_format" validate:"log_format"`
+	Level  string `mapstructure:"log_level" validate:"log_level"`
 }
 
 // HttpConfig represents the configuration for an HTTP server.
@@ -108,7 +108,7 @@ type LoggingConfig struct {
 //   - Validates as either an IPv4 address or a hostname compliant with RFC1123.
 //   - This field is required.
 //   - Port: Specifies the port number for the HTTP server.
-//   - Validates as a numeric value between 1025 and 65,535 (exclusive).
+//   - Validates as an unprivileged port, 1025 to 65,535 (inclusive).
 //   - This field is required.
 //   - ReadTimeout: Specifies the maximum duration for reading the entire request, including the body.
 //   - Validates as a duration between 100 ms and 1 s (inclusive).
@@ -119,12 +119,12 @@ type LoggingConfig struct {
 //   - ShutdownTimeout: Specifies the maximum duration to wait for active connections to close gracefully during shutdown.
 //   - Validates as a duration between 100 ms and 30 s (inclusive).
 type HttpConfig struct {
-	Host            string        `mapstructure:"http_host" validate:"ip4_addr|hostname_rfc1123,required"`
-	Port            int           `mapstructure:"http_port" validate:"numeric,gt=1024,lt=65536,required"`
-	ReadTimeout     time.Duration `mapstructure:"http_read_timeout" validate:"min=100ms,max=1s"`
-	WriteTimeout    time.Duration `mapstructure:"http_write_timeout" validate:"min=100ms,max=1s"`
-	IdleTimeout     time.Duration `mapstructure:"http_idle_timeout" validate:"min=100ms,max=1s"`
-	ShutdownTimeout time.Duration `mapstructure:"http_shutdown_timeout" validate:"min=100ms,max=30s"`
+	Host            string        `mapstructure:"http_host" validate:"host_or_ip,required"`
+	Port            int           `mapstructure:"http_port" validate:"svc_port,required"`
+	ReadTimeout     time.Duration `mapstructure:"http_read_timeout" validate:"duration_between=100ms:1s"`
+	WriteTimeout    time.Duration `mapstructure:"http_write_timeout" validate:"duration_between=100ms:1s"`
+	IdleTimeout     time.Duration `mapstructure:"http_idle_timeout" validate:"duration_between=100ms:1s"`
+	ShutdownTimeout time.Duration `mapstructure:"http_shutdown_timeout" validate:"duration_between=100ms:30s"`
 }
 
 // OtelConfig represents the configuration for OpenTelemetry (OTel) tracing systems.
@@ -142,7 +142,7 @@ type HttpConfig struct {
 // It enables robust setup and shutdown of Otel tracing in microservices.
 type OtelConfig struct {
 	Endpoint        string        `mapstructure:"otel_endpoint" validate:"url,urlprefix,required"`
-	ShutdownTimeout time.Duration `mapstructure:"otel_shutdown_timeout" validate:"min=100ms,max=30s"`
+	ShutdownTimeout time.Duration `mapstructure:"otel_shutdown_timeout" validate:"duration_between=100ms:30s"`
 }
 
 // RateLimiterConfig represents the configuration for a rate-limiting middleware.
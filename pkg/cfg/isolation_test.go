@@ -0,0 +1,49 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type HostConfig struct {
+	Host string `mapstructure:"host"`
+}
+
+func TestNewConfig_IsolatedKeys(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("HM_API_HOST", "api.local")
+	t.Setenv("HM_DB_HOST", "db.local")
+	api, db, cache := &HostConfig{}, &HostConfig{}, &HostConfig{}
+	require.NoError(t, RegisterConfig("api", ConfigEntry{
+		Config:    api,
+		BindArray: []BindValue{{ValName: "host", Aliases: []string{"api_host"}}},
+	}))
+	require.NoError(t, RegisterConfig("db", ConfigEntry{
+		Config:    db,
+		BindArray: []BindValue{{ValName: "host", Aliases: []string{"db_host"}}},
+	}))
+	require.NoError(t, RegisterConfig("cache", ConfigEntry{
+		Config:    cache,
+		BindArray: []BindValue{{ValName: "host", DefaultVal: "cache.local"}},
+	}))
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+	assert.Equal(t, "api.local", api.Host)
+	assert.Equal(t, "db.local", db.Host)
+	assert.Equal(t, "cache.local", cache.Host, "expected the default to apply to its own entry only")
+
+	require.NoError(t, SetValue("api", "host", "override.local"))
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+	api, err := Get[HostConfig]("api")
+	require.NoError(t, err)
+	assert.Equal(t, "override.local", api.Host)
+	assert.Equal(t, "db.local", db.Host, "expected overrides to apply to their own entry only")
+	assert.Equal(t, "cache.local", cache.Host)
+
+	t.Setenv("HM_HOST", "shared.local")
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+	assert.Equal(t, "shared.local", db.Host, "expected env var names to stay unchanged")
+	assert.Equal(t, "shared.local", cache.Host)
+}
@@ -0,0 +1,89 @@
+package cfg
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestRegisterConfig_AfterNewConfig(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("LOG_LEVEL", "debug")
+	require.NoError(t, NewConfig(WithValidation()))
+
+	late := &LevelConfig{}
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    late,
+		BindArray: []BindValue{{ValName: "log_level"}},
+	}))
+	assert.Equal(t, "debug", late.Level, "expected a late registration to be loaded at once")
+
+	t.Setenv("BAD_LOG_LEVEL", "verbose")
+	err := RegisterConfig("watched", ConfigEntry{
+		Config:    &WatchedConfig{},
+		BindArray: []BindValue{{ValName: "log_level"}},
+		EnvPrefix: "BAD",
+	})
+	assert.ErrorContains(t, err, "config 'watched': validation failed")
+	_, ok := GetConfig("watched")
+	assert.False(t, ok, "expected a late registration failing to load not to be stored")
+}
+
+func TestLoadEntry(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := &LevelConfig{}
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+	}))
+	t.Setenv("FIELD2", "not a number")
+	require.NoError(t, RegisterConfig("broken", ConfigEntry{
+		Config:    &TestConfig{},
+		BindArray: []BindValue{{ValName: "field2"}},
+	}))
+
+	require.NoError(t, LoadEntry("log"), "expected other entries not to be loaded")
+	assert.Equal(t, "info", conf.Level)
+	assert.Equal(t, []EntryInfo{
+		{Name: "broken", Type: "cfg.TestConfig", BindValues: 1, Loaded: false},
+		{Name: "log", Type: "cfg.LevelConfig", BindValues: 1, Loaded: true},
+	}, ListEntries())
+
+	assert.ErrorContains(t, LoadEntry("missing"), "config 'missing' is not registered")
+}
+
+func TestRegisterConfig_Concurrent(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("LOG_LEVEL", "warn")
+	require.NoError(t, NewConfig())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.NoError(t, RegisterConfig(fmt.Sprintf("log-%d-%d", i, j), ConfigEntry{
+					Config:    &LevelConfig{},
+					BindArray: []BindValue{{ValName: "log_level"}},
+				}))
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if conf, ok := GetConfig(fmt.Sprintf("log-%d-%d", i, j)); ok {
+					assert.Equal(t, "warn", conf.(*LevelConfig).Level)
+				}
+				_ = ListEntries()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, ListConfigs(), 160)
+}
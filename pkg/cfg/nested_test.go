@@ -0,0 +1,59 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type NestedConfig struct {
+	Name string `mapstructure:"name"`
+	DB   struct {
+		Host string `mapstructure:"host"`
+		Pool struct {
+			MaxOpen int           `mapstructure:"max_open"`
+			MaxIdle time.Duration `mapstructure:"max_idle"`
+		} `mapstructure:"pool"`
+	} `mapstructure:"db"`
+}
+
+func TestAutoBind(t *testing.T) {
+	assert.Equal(t, []BindValue{
+		{ValName: "name"},
+		{ValName: "db.host"},
+		{ValName: "db.pool.max_open"},
+		{ValName: "db.pool.max_idle"},
+	}, AutoBind(&NestedConfig{}))
+	assert.Nil(t, AutoBind(NestedConfig{}))
+	assert.Nil(t, AutoBind(nil))
+}
+
+func TestNewConfig_NestedSections(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("HM_NAME", "svc")
+	t.Setenv("HM_DB_HOST", "db.local")
+	t.Setenv("HM_DB_POOL_MAX_OPEN", "20")
+	t.Setenv("HM_DB_POOL_MAX_IDLE", "30s")
+	conf := &NestedConfig{}
+	require.NoError(t, RegisterConfig("svc", ConfigEntry{Config: conf, BindArray: AutoBind(conf)}))
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithStrictEnv()))
+	assert.Equal(t, "svc", conf.Name)
+	assert.Equal(t, "db.local", conf.DB.Host)
+	assert.Equal(t, 20, conf.DB.Pool.MaxOpen)
+	assert.Equal(t, 30*time.Second, conf.DB.Pool.MaxIdle)
+}
+
+func TestNewConfig_NestedRequired(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("svc", ConfigEntry{
+		Config:    &NestedConfig{},
+		BindArray: []BindValue{{ValName: "db.pool.max_open", Required: true}},
+	}))
+
+	err := NewConfig(WithSetEnvPrefix("HM"))
+	assert.ErrorContains(t, err, "required value db.pool.max_open is not set: set HM_DB_POOL_MAX_OPEN")
+}
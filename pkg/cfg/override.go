@@ -0,0 +1,88 @@
+package cfg
+
+import (
+	"fmt"
+	"github.com/KennyMacCormik/HerdMaster/pkg/val"
+	"github.com/spf13/viper"
+	"reflect"
+	"slices"
+)
+
+// SetValue overrides key of the config registered as entryName at runtime, e.g. from an admin
+// endpoint flipping a feature flag. The entry is unmarshalled into a temporary struct and validated
+// with the val package; on failure the override is rolled back and the stored config is kept.
+// On success the temporary struct is copied into the registered one under the write lock, like
+// NewConfig does, so the pointer passed to RegisterConfig and those returned by GetConfig and Get
+// observe the new value.
+//
+// Overrides take precedence over flags, environment variables, file values and defaults,
+// and are reapplied by later NewConfig calls and Watch reloads until Reset.
+// Key must be the ValName of one of the entry bindings.
+//
+// Example usage:
+//
+//	if err := cfg.SetValue("features", "new_checkout", true); err != nil {
+//		// Handle error
+//	}
+func SetValue(entryName, key string, value any) error {
+	return defaultRegistry.SetValue(entryName, key, value)
+}
+
+// SetValue overrides key of the config registered in r as entryName. See the package-level SetValue.
+func (r *Registry) SetValue(entryName, key string, value any) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	entry, ok := r.entries[entryName]
+	if !ok {
+		return fmt.Errorf("config '%s' is not registered", entryName)
+	}
+	if !slices.ContainsFunc(entry.BindArray, func(b BindValue) bool { return b.ValName == key }) {
+		return fmt.Errorf("key %s is not bound by config '%s'", key, entryName)
+	}
+
+	rollback := r.setOverride(entryName, key, value)
+	registered := entry.Config
+	entry.Config = reflect.New(reflect.TypeOf(registered).Elem()).Interface()
+	sources, err := r.bindActualValue(entryName, &entry)
+	if err == nil {
+		err = val.GetValidator().ValidateStruct(entry.Config)
+	}
+	if err != nil {
+		rollback()
+		return &EntryError{Name: entryName, Err: fmt.Errorf("failed to set %s: %w", key, err)}
+	}
+	storeInPlace(registered, entry.Config)
+	r.sources[entryName] = sources
+	return nil
+}
+
+// storeInPlace copies the struct decoded points to into the registered config, so pointers
+// to the registered config observe the update. The caller must hold the write lock of mtx.
+func storeInPlace(registered, decoded any) {
+	reflect.ValueOf(registered).Elem().Set(reflect.ValueOf(decoded).Elem())
+}
+
+// setOverride stores value as the override of key for entryName and returns a function
+// restoring the previous override. The caller must hold mtx.
+func (r *Registry) setOverride(entryName, key string, value any) func() {
+	if r.overrides[entryName] == nil {
+		r.overrides[entryName] = make(map[string]any)
+	}
+	prev, hadPrev := r.overrides[entryName][key]
+	r.overrides[entryName][key] = value
+	return func() {
+		if hadPrev {
+			r.overrides[entryName][key] = prev
+			return
+		}
+		delete(r.overrides[entryName], key)
+	}
+}
+
+// applyOverrides sets the values stored by SetValue for the entry registered as name. The caller must hold mtx.
+func (r *Registry) applyOverrides(v *viper.Viper, name string) {
+	for key, value := range r.overrides[name] {
+		v.Set(key, value)
+	}
+}
@@ -0,0 +1,91 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type FeatureConfig struct {
+	Checkout bool   `mapstructure:"new_checkout"`
+	Level    string `mapstructure:"log_level" validate:"oneof=debug info warn error"`
+}
+
+func registerFeatureConfig(t *testing.T) *FeatureConfig {
+	t.Helper()
+	conf := &FeatureConfig{}
+	require.NoError(t, RegisterConfig("features", ConfigEntry{
+		Config: conf,
+		BindArray: []BindValue{
+			{ValName: "new_checkout"},
+			{ValName: "log_level", DefaultVal: "info"},
+		},
+	}))
+	return conf
+}
+
+func TestSetValue(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("NEW_CHECKOUT", "false")
+	loaded := registerFeatureConfig(t)
+	require.NoError(t, NewConfig())
+
+	require.NoError(t, SetValue("features", "new_checkout", true))
+	current, err := Get[FeatureConfig]("features")
+	require.NoError(t, err)
+	assert.True(t, current.Checkout, "expected the override to win over the environment")
+	assert.Equal(t, "info", current.Level)
+	assert.Same(t, loaded, current, "expected the registered struct to be kept")
+	assert.True(t, loaded.Checkout, "expected the registered struct to be updated in place")
+
+	require.NoError(t, NewConfig())
+	current, err = Get[FeatureConfig]("features")
+	require.NoError(t, err)
+	assert.True(t, current.Checkout, "expected NewConfig to reapply the override")
+}
+
+func TestSetValue_ValidationRollback(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	loaded := registerFeatureConfig(t)
+	require.NoError(t, NewConfig())
+	require.NoError(t, SetValue("features", "log_level", "debug"))
+
+	err := SetValue("features", "log_level", "verbose")
+	var entryErr *EntryError
+	require.ErrorAs(t, err, &entryErr)
+	assert.Equal(t, "features", entryErr.Name)
+	assert.ErrorContains(t, err, "failed to set log_level")
+
+	current, err := Get[FeatureConfig]("features")
+	require.NoError(t, err)
+	assert.Equal(t, "debug", current.Level, "expected the previous value to be kept")
+	assert.Equal(t, "debug", loaded.Level, "expected the registered struct to be left untouched")
+
+	require.NoError(t, NewConfig())
+	current, err = Get[FeatureConfig]("features")
+	require.NoError(t, err)
+	assert.Equal(t, "debug", current.Level, "expected the rolled back override not to be reapplied")
+}
+
+func TestSetValue_Errors(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	registerFeatureConfig(t)
+
+	assert.ErrorContains(t, SetValue("missing", "log_level", "debug"), "config 'missing' is not registered")
+	assert.ErrorContains(t, SetValue("features", "unknown", 1), "key unknown is not bound by config 'features'")
+}
+
+func TestReset_ClearsOverrides(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	registerFeatureConfig(t)
+	require.NoError(t, SetValue("features", "new_checkout", true))
+
+	Reset()
+	conf := registerFeatureConfig(t)
+	require.NoError(t, NewConfig())
+	assert.False(t, conf.Checkout, "expected Reset to drop overrides")
+}
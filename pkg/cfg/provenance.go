@@ -0,0 +1,108 @@
+package cfg
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"maps"
+	"os"
+)
+
+// Source tells where the value of a config key came from.
+type Source string
+
+const (
+	SourceOverride   Source = "override"    // Set by SetValue
+	SourceFlag       Source = "flag"        // Set on the command line, see WithFlags
+	SourceSecretFile Source = "secret_file" // Read from a <NAME>_FILE secret
+	SourceEnv        Source = "env"         // Read from an environment variable
+	SourceEnvFile    Source = "env_file"    // Read from the file set by WithEnvFile
+	SourceFile       Source = "file"        // Read from the file set by WithConfigFile
+	SourceDefault    Source = "default"     // BindValue.DefaultVal or DefaultFunc
+	SourceUnset      Source = "unset"       // No value, the field keeps its zero value
+)
+
+// Explain returns the source of every bound key of the config registered as entryName,
+// as of the last NewConfig call, Watch reload or SetValue. It answers questions like
+// "why is the port 8080": a default, an environment variable or a file value.
+//
+// Example usage:
+//
+//	sources, err := cfg.Explain("http")
+//	if err != nil {
+//		// Handle error
+//	}
+//	logger.Info("http config sources", "sources", sources)
+func Explain(entryName string) (map[string]Source, error) {
+	return defaultRegistry.Explain(entryName)
+}
+
+// Explain returns the source of every bound key of the config registered in r as entryName.
+// See the package-level Explain.
+func (r *Registry) Explain(entryName string) (map[string]Source, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	if _, ok := r.entries[entryName]; !ok {
+		return nil, fmt.Errorf("config '%s' is not registered", entryName)
+	}
+	sources, ok := r.sources[entryName]
+	if !ok {
+		return nil, fmt.Errorf("config '%s' has not been loaded", entryName)
+	}
+	return maps.Clone(sources), nil
+}
+
+// sourcesOf returns the source of every bound key of the entry registered as name,
+// following viper precedence. The caller must hold mtx.
+func (r *Registry) sourcesOf(v *viper.Viper, name string, entry *ConfigEntry) map[string]Source {
+	prefix := r.entryEnvPrefix(entry)
+	fs := r.flagSet.Load()
+	sources := make(map[string]Source, len(entry.BindArray))
+	for _, e := range entry.BindArray {
+		env := envName(prefix, e.ValName)
+		_, overridden := r.overrides[name][e.ValName]
+		switch {
+		case overridden:
+			sources[e.ValName] = SourceOverride
+		case fs != nil && fs.Changed(flagName(e.ValName)):
+			sources[e.ValName] = SourceFlag
+		case r.isFileSecret(env):
+			sources[e.ValName] = SourceSecretFile
+		case envSet(prefix, e):
+			sources[e.ValName] = SourceEnv
+		case r.envFileSet(prefix, e):
+			sources[e.ValName] = SourceEnvFile
+		case v.InConfig(e.ValName):
+			sources[e.ValName] = SourceFile
+		case isNotNullOrDefault(e.DefaultVal) || e.DefaultFunc != nil:
+			sources[e.ValName] = SourceDefault
+		default:
+			sources[e.ValName] = SourceUnset
+		}
+	}
+	return sources
+}
+
+// envSet reports whether the primary variable of e or one of its aliases is set.
+func envSet(prefix string, e BindValue) bool {
+	if os.Getenv(envName(prefix, e.ValName)) != "" {
+		return true
+	}
+	for _, alias := range e.Aliases {
+		if os.Getenv(envName(prefix, alias)) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isFileSecret reports whether bindFileSecrets read the variable env from a _FILE secret.
+func (r *Registry) isFileSecret(env string) bool {
+	if !r.fileSecretsOnLoad.Load() {
+		return false
+	}
+	if _, ok := os.LookupEnv(env + "_FILE"); !ok {
+		return false
+	}
+	return !r.plainEnvSet(env)
+}
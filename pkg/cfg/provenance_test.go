@@ -0,0 +1,67 @@
+package cfg
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type ExplainedConfig struct {
+	Port    int    `mapstructure:"http_port"`
+	Host    string `mapstructure:"http_host"`
+	Level   string `mapstructure:"log_level"`
+	Token   string `mapstructure:"api_token"`
+	Debug   bool   `mapstructure:"debug"`
+	Feature bool   `mapstructure:"feature"`
+	Unset   string `mapstructure:"unset"`
+}
+
+func TestExplain(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	secret := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cret\n"), 0o600))
+	t.Setenv("HM_HTTP_HOST", "example.com")
+	t.Setenv("HM_API_TOKEN_FILE", secret)
+
+	require.NoError(t, RegisterConfig("http", ConfigEntry{
+		Config: &ExplainedConfig{},
+		BindArray: []BindValue{
+			{ValName: "http_port", DefaultVal: 8080},
+			{ValName: "http_host"},
+			{ValName: "log_level", DefaultVal: "info"},
+			{ValName: "api_token"},
+			{ValName: "debug"},
+			{ValName: "feature"},
+			{ValName: "unset"},
+		},
+	}))
+	path := writeConfigFile(t, "config.yaml", "log_level: debug\n")
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags := WithFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--debug=true"}))
+
+	_, err := Explain("http")
+	assert.ErrorContains(t, err, "config 'http' has not been loaded")
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM"), WithConfigFile(path, ""), WithFileSecrets(), flags))
+	require.NoError(t, SetValue("http", "feature", true))
+
+	sources, err := Explain("http")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Source{
+		"http_port": SourceDefault,
+		"http_host": SourceEnv,
+		"log_level": SourceFile,
+		"api_token": SourceSecretFile,
+		"debug":     SourceFlag,
+		"feature":   SourceOverride,
+		"unset":     SourceUnset,
+	}, sources)
+
+	_, err = Explain("missing")
+	assert.ErrorContains(t, err, "config 'missing' is not registered")
+}
@@ -0,0 +1,630 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"github.com/KennyMacCormik/HerdMaster/pkg/val"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry is an independent configuration system: it owns its registered entries,
+// its Viper instance and its lock. The package-level functions operate on a default
+// Registry backed by the global Viper instance, so most applications never create one.
+// Use NewRegistry to embed several configuration systems in one binary, e.g. in a plugin host.
+//
+// Example usage:
+//
+//	r := cfg.NewRegistry()
+//	if err := r.Register("log", entry); err != nil {
+//		// Handle error
+//	}
+//	if err := r.Load(cfg.WithSetEnvPrefix("plugin")); err != nil {
+//		// Handle error
+//	}
+//	logConfig, err := cfg.GetFrom[LoggingConfig](r, "log")
+type Registry struct {
+	mtx     sync.RWMutex
+	v       atomic.Pointer[viper.Viper] // nil selects the global Viper instance
+	entries map[string]ConfigEntry
+	// overrides holds the values set by SetValue per entry name and key.
+	overrides map[string]map[string]any
+	// sources holds the source of each bound key per entry name, as of the last load.
+	sources map[string]map[string]Source
+	// loaded is set by the first Load call, after which Register loads new entries at once.
+	loaded bool
+	// warnings holds the non-fatal problems found by the last Load call.
+	warnings []error
+
+	// validateOnLoad is set by WithValidation for the duration of a Load call.
+	validateOnLoad atomic.Bool
+	// fileSecretsOnLoad is set by WithFileSecrets for the duration of a Load call.
+	fileSecretsOnLoad atomic.Bool
+	// flagSet is the parsed flag set supplied by WithFlags for the duration of a Load call.
+	flagSet atomic.Pointer[pflag.FlagSet]
+	// strictEnv is set by WithStrictEnv for the duration of a Load call.
+	strictEnv atomic.Bool
+	// allowedEnv holds the variables allowed by WithStrictEnv, guarded by mtx.
+	allowedEnv map[string]struct{}
+	// unknownEnv holds the prefixed variables matching no binding as of the last Load call.
+	unknownEnv []string
+	// envFile holds the variables read by WithEnvFile, guarded by mtx.
+	envFile map[string]string
+	// decodeHooks holds the hooks added by WithDecodeHook, guarded by mtx.
+	decodeHooks []mapstructure.DecodeHookFunc
+	// envPrefix is the prefix set by WithSetEnvPrefix.
+	envPrefix string
+	// configFileFormat is the format of the file set by WithConfigFile.
+	configFileFormat string
+
+	watchMtx sync.Mutex
+	// watchedViper is the viper instance whose file watcher has been started.
+	// Viper watchers cannot be stopped, so each instance is watched at most once.
+	watchedViper *viper.Viper
+	// watchEvents receives a signal per config file change while a Watch call is active.
+	watchEvents chan struct{}
+}
+
+// NewRegistry returns an empty Registry with its own Viper instance.
+func NewRegistry() *Registry {
+	return newRegistry(viper.New())
+}
+
+func newRegistry(v *viper.Viper) *Registry {
+	r := &Registry{
+		entries:   make(map[string]ConfigEntry),
+		overrides: make(map[string]map[string]any),
+		sources:   make(map[string]map[string]Source),
+	}
+	r.v.Store(v)
+	return r
+}
+
+// Viper returns the registry Viper instance holding the config file and environment prefix
+// set by options, for options that need to customize it. Entries are loaded from their own
+// instances created from these settings, so values set directly on it are not seen by entries.
+func (r *Registry) Viper() *viper.Viper {
+	if v := r.v.Load(); v != nil {
+		return v
+	}
+	return viper.GetViper()
+}
+
+// Load applies the options, binds all registered configuration entries, and loads their values
+// from environment variables and, if WithConfigFile is supplied, a config file.
+func (r *Registry) Load(list ...ViperOption) error {
+	r.validateOnLoad.Store(false)
+	r.fileSecretsOnLoad.Store(false)
+	r.flagSet.Store(nil)
+	r.strictEnv.Store(false)
+	r.mtx.Lock()
+	r.decodeHooks = nil
+	r.allowedEnv = nil
+	r.envFile = nil
+	r.mtx.Unlock()
+	for _, opt := range list {
+		err := opt(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.Viper().AutomaticEnv()
+
+	return r.newConfigWithLock()
+}
+
+// Register adds a custom configuration struct along with its environment variable bindings.
+// It returns an error if name is already registered.
+func (r *Registry) Register(name string, configStruct ConfigEntry) error {
+	return r.register(name, configStruct, false)
+}
+
+// RegisterOverride adds a configuration struct like Register, replacing any entry already
+// registered under name together with its SetValue overrides.
+func (r *Registry) RegisterOverride(name string, configStruct ConfigEntry) error {
+	return r.register(name, configStruct, true)
+}
+
+// register validates and stores configStruct under name, replacing an existing entry only if override is set.
+func (r *Registry) register(name string, configStruct ConfigEntry, override bool) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+
+	if err := validateConfigStruct(configStruct); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+
+	if err := validateBindArray(configStruct.BindArray); err != nil {
+		return fmt.Errorf("bind array validation failed: %w", err)
+	}
+
+	if err := validateDefaults(configStruct); err != nil {
+		return &EntryError{Name: name, Err: fmt.Errorf("default validation failed: %w", err)}
+	}
+
+	return r.storeConfigStructWithLock(name, configStruct, override)
+}
+
+// Unregister removes a registered configuration entry by name.
+// It returns an error if no entry is registered under name.
+func (r *Registry) Unregister(name string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.entries[name]; !ok {
+		return fmt.Errorf("config '%s' is not registered", name)
+	}
+	delete(r.entries, name)
+	delete(r.overrides, name)
+	delete(r.sources, name)
+	return nil
+}
+
+// Reset removes all registered configuration entries together with the values set by SetValue,
+// and drops the settings of earlier options such as the environment prefix and the config file,
+// so configs can be registered and loaded again from scratch. The Viper instance is replaced;
+// for the default registry, that is the global instance, which is reset with viper.Reset.
+func (r *Registry) Reset() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.entries = make(map[string]ConfigEntry)
+	r.overrides = make(map[string]map[string]any)
+	r.sources = make(map[string]map[string]Source)
+	r.warnings = nil
+	r.unknownEnv = nil
+	r.loaded = false
+
+	r.validateOnLoad.Store(false)
+	r.fileSecretsOnLoad.Store(false)
+	r.flagSet.Store(nil)
+	r.strictEnv.Store(false)
+	r.allowedEnv = nil
+	r.envFile = nil
+	r.decodeHooks = nil
+	r.envPrefix = ""
+	r.configFileFormat = ""
+	if r.v.Load() == nil {
+		viper.Reset()
+	} else {
+		r.v.Store(viper.New())
+	}
+}
+
+// List returns the names of all registered configurations.
+func (r *Registry) List() []string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	keys := make([]string, 0, len(r.entries))
+	for k := range r.entries {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ListEntries returns the metadata of the configurations registered in r, ordered by name.
+func (r *Registry) ListEntries() []EntryInfo {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	infos := make([]EntryInfo, 0, len(r.entries))
+	for _, name := range r.sortedEntryNames() {
+		entry := r.entries[name]
+		_, loaded := r.sources[name]
+		infos = append(infos, EntryInfo{
+			Name:       name,
+			Type:       reflect.TypeOf(entry.Config).Elem().String(),
+			BindValues: len(entry.BindArray),
+			Loaded:     loaded,
+		})
+	}
+	return infos
+}
+
+// Get retrieves a registered configuration struct by name.
+func (r *Registry) Get(name string) (any, bool) {
+	v, ok := r.getConfigWithRLock(name)
+	return v.Config, ok
+}
+
+// GetFrom retrieves a configuration struct registered in r by name as a *T.
+// It returns an error if name is not registered or if the stored Config is not a *T.
+func GetFrom[T any](r *Registry, name string) (*T, error) {
+	v, ok := r.getConfigWithRLock(name)
+	if !ok {
+		return nil, fmt.Errorf("config '%s' is not registered", name)
+	}
+	typed, ok := v.Config.(*T)
+	if !ok {
+		return nil, fmt.Errorf("config '%s' is %T, not %T", name, v.Config, (*T)(nil))
+	}
+	return typed, nil
+}
+
+// Warnings returns the non-fatal problems found by the last Load call,
+// such as a variable set both directly and through a _FILE secret.
+func (r *Registry) Warnings() []error {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return slices.Clone(r.warnings)
+}
+
+// WithFlags returns an option declaring a flag on fs for every BindValue of the configs
+// registered in r so far. See the package-level WithFlags.
+func (r *Registry) WithFlags(fs *pflag.FlagSet) ViperOption {
+	if fs == nil {
+		return func(*Registry) error {
+			return fmt.Errorf("flag set cannot be nil")
+		}
+	}
+	r.declareFlags(fs)
+	return func(r *Registry) error {
+		if !fs.Parsed() {
+			return fmt.Errorf("flag set must be parsed before NewConfig")
+		}
+		r.flagSet.Store(fs)
+		return nil
+	}
+}
+
+// declareFlags declares a flag typed after DefaultVal for every registered BindValue
+// that has no flag on fs yet.
+func (r *Registry) declareFlags(fs *pflag.FlagSet) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	for _, name := range r.sortedEntryNames() {
+		for _, e := range r.entries[name].BindArray {
+			flag := flagName(e.ValName)
+			if fs.Lookup(flag) != nil {
+				continue
+			}
+			usage := fmt.Sprintf("sets %s of config %s", e.ValName, name)
+			switch def := e.DefaultVal.(type) {
+			case string:
+				fs.String(flag, def, usage)
+			case int:
+				fs.Int(flag, def, usage)
+			case bool:
+				fs.Bool(flag, def, usage)
+			case float64:
+				fs.Float64(flag, def, usage)
+			case time.Duration:
+				fs.Duration(flag, def, usage)
+			case []string:
+				fs.StringSlice(flag, def, usage)
+			case nil:
+				fs.String(flag, "", usage)
+			default:
+				fs.String(flag, fmt.Sprint(def), usage)
+			}
+		}
+	}
+}
+
+// newConfigWithLock acquires a write lock and iterates through all registered configuration entries
+// to bind and load their values from the environment.
+// If WithValidation was supplied, it then validates each loaded entry.
+// It does not stop at the first failing entry: all failures are returned as ConfigErrors.
+func (r *Registry) newConfigWithLock() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.warnings = nil
+	r.loaded = true
+	var errs ConfigErrors
+	for _, name := range r.sortedEntryNames() {
+		if err := r.loadEntryLocked(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	r.unknownEnv = r.scanUnknownEnv()
+	if r.strictEnv.Load() && len(r.unknownEnv) > 0 {
+		return fmt.Errorf("unknown environment variables: %s", strings.Join(r.unknownEnv, ", "))
+	}
+	return nil
+}
+
+// LoadEntry binds and unmarshals only the configuration entry registered in r as name,
+// validating it if the last Load used WithValidation. See the package-level LoadEntry.
+func (r *Registry) LoadEntry(name string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.entries[name]; !ok {
+		return fmt.Errorf("config '%s' is not registered", name)
+	}
+	if err := r.loadEntryLocked(name); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadEntryLocked binds, unmarshals and, if WithValidation was supplied, validates the entry
+// registered as name. The caller must hold mtx.
+func (r *Registry) loadEntryLocked(name string) *EntryError {
+	entry := r.entries[name]
+	sources, err := r.bindActualValue(name, &entry)
+	if err != nil {
+		return &EntryError{Name: name, Err: fmt.Errorf("failed to bind: %w", err)}
+	}
+	r.sources[name] = sources
+	if !r.validateOnLoad.Load() {
+		return nil
+	}
+	if err := val.GetValidator().ValidateStruct(entry.Config); err != nil {
+		return &EntryError{Name: name, Err: fmt.Errorf("validation failed: %w", err)}
+	}
+	return nil
+}
+
+// sortedEntryNames returns the registered config names in sorted order. The caller must hold mtx.
+func (r *Registry) sortedEntryNames() []string {
+	names := make([]string, 0, len(r.entries))
+	for k := range r.entries {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// storeConfigStructWithLock safely stores a configuration struct in the registry with a write lock.
+// Unless override is set, it returns an error if name is already registered.
+// If the registry has already been loaded, the entry is loaded at once and is not stored if that fails.
+func (r *Registry) storeConfigStructWithLock(name string, configStruct ConfigEntry, override bool) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	prev, exists := r.entries[name]
+	if exists {
+		if !override {
+			return fmt.Errorf("config '%s' is already registered", name)
+		}
+		delete(r.overrides, name)
+		delete(r.sources, name)
+	}
+	r.entries[name] = configStruct
+	if !r.loaded {
+		return nil
+	}
+
+	if err := r.loadEntryLocked(name); err != nil {
+		delete(r.sources, name)
+		if exists {
+			r.entries[name] = prev
+		} else {
+			delete(r.entries, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// getConfigWithRLock retrieves a registered configuration struct by name with a read lock.
+func (r *Registry) getConfigWithRLock(name string) (ConfigEntry, bool) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	v, ok := r.entries[name]
+	return v, ok
+}
+
+// bindActualValue binds environment variables to the configuration entry registered as name
+// and unmarshals the environment values into the Config field.
+// It returns the source of each bound key, which the caller stores once it keeps the loaded config.
+func (r *Registry) bindActualValue(name string, entry *ConfigEntry) (map[string]Source, error) {
+	v, err := r.entryViper(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.bindToEnv(v, entry); err != nil {
+		return nil, err
+	}
+
+	r.bindEnvFile(v, entry)
+
+	if r.fileSecretsOnLoad.Load() {
+		if err := r.bindFileSecrets(v, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	r.applyOverrides(v, name)
+
+	if err := r.checkRequired(v, entry); err != nil {
+		return nil, err
+	}
+
+	if err := v.Unmarshal(entry.Config, viper.DecodeHook(r.decodeHook())); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal into config: %w", err)
+	}
+
+	return r.sourcesOf(v, name, entry), nil
+}
+
+// decodeHook returns the default decode hooks followed by the hooks added by WithDecodeHook.
+// The caller must hold mtx.
+func (r *Registry) decodeHook() mapstructure.DecodeHookFunc {
+	hooks := append([]mapstructure.DecodeHookFunc{
+		mapstructure.StringToTimeDurationHookFunc(),
+		// net.IP is a byte slice, so it must be parsed before strings are split into slices
+		mapstructure.StringToIPHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	}, r.decodeHooks...)
+	return mapstructure.ComposeDecodeHookFunc(hooks...)
+}
+
+// bindToEnv binds environment variables, flags supplied by WithFlags, and default values
+// for a configuration entry.
+func (r *Registry) bindToEnv(v *viper.Viper, entry *ConfigEntry) error {
+	prefix := r.entryEnvPrefix(entry)
+	for _, e := range entry.BindArray {
+		if err := v.BindEnv(bindEnvArgs(prefix, e)...); err != nil {
+			return fmt.Errorf("failed to bind %s: %w", e.ValName, err)
+		}
+		r.warnDeprecatedAliases(prefix, e)
+		if fs := r.flagSet.Load(); fs != nil {
+			if flag := fs.Lookup(flagName(e.ValName)); flag != nil {
+				if err := v.BindPFlag(e.ValName, flag); err != nil {
+					return fmt.Errorf("failed to bind flag %s: %w", flag.Name, err)
+				}
+			}
+		}
+		def, err := defaultOf(e)
+		if err != nil {
+			return err
+		}
+		if e.DefaultFunc != nil {
+			// DefaultVal is checked at registration, DefaultFunc results only now
+			if err := checkDefault(fieldsByKey(reflect.TypeOf(entry.Config).Elem()), e.ValName, def); err != nil {
+				return fmt.Errorf("DefaultFunc returned an invalid default: %w", err)
+			}
+		}
+		if isNotNullOrDefault(def) {
+			v.SetDefault(e.ValName, def)
+		}
+	}
+	return nil
+}
+
+// defaultOf returns the default of e, calling DefaultFunc if set.
+// An error returned or a panic raised by DefaultFunc is returned as an error naming the key.
+func defaultOf(e BindValue) (def any, err error) {
+	if e.DefaultFunc == nil {
+		return e.DefaultVal, nil
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("failed to compute default of %s: panic: %v", e.ValName, p)
+		}
+	}()
+	def = e.DefaultFunc()
+	if fnErr, ok := def.(error); ok {
+		return nil, fmt.Errorf("failed to compute default of %s: %w", e.ValName, fnErr)
+	}
+	return def, nil
+}
+
+// bindEnvArgs returns the viper.BindEnv arguments of e: the key alone, or the key followed by
+// the primary variable and the alias variables, in precedence order.
+func bindEnvArgs(prefix string, e BindValue) []string {
+	if len(e.Aliases) == 0 {
+		return []string{e.ValName}
+	}
+	args := []string{e.ValName, envName(prefix, e.ValName)}
+	for _, alias := range e.Aliases {
+		args = append(args, envName(prefix, alias))
+	}
+	return args
+}
+
+// warnDeprecatedAliases records a warning for every alias of e that is set while the primary
+// variable is not. The caller must hold mtx.
+func (r *Registry) warnDeprecatedAliases(prefix string, e BindValue) {
+	primary := envName(prefix, e.ValName)
+	if len(e.Aliases) == 0 || os.Getenv(primary) != "" {
+		return
+	}
+	for _, alias := range e.Aliases {
+		if name := envName(prefix, alias); os.Getenv(name) != "" {
+			r.warn(fmt.Errorf("%s is deprecated, use %s", name, primary))
+		}
+	}
+}
+
+// warn records a non-fatal problem returned by Warnings, once per message. The caller must hold mtx.
+func (r *Registry) warn(err error) {
+	for _, w := range r.warnings {
+		if w.Error() == err.Error() {
+			return
+		}
+	}
+	r.warnings = append(r.warnings, err)
+}
+
+// bindFileSecrets sets the values of the entry bindings that have a <NAME>_FILE variable,
+// no plain variable in the environment or the env file, and no flag set on the command line.
+// The caller must hold mtx.
+func (r *Registry) bindFileSecrets(v *viper.Viper, entry *ConfigEntry) error {
+	prefix := r.entryEnvPrefix(entry)
+	flags := r.flagSet.Load()
+	for _, e := range entry.BindArray {
+		name := envName(prefix, e.ValName)
+		fileVar := name + "_FILE"
+		path, ok := os.LookupEnv(fileVar)
+		if !ok || (flags != nil && flags.Changed(flagName(e.ValName))) {
+			continue
+		}
+		if r.plainEnvSet(name) {
+			r.warn(fmt.Errorf("both %s and %s are set for %s, using %s", name, fileVar, e.ValName, name))
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read secret file for %s from %s: %w", e.ValName, fileVar, err)
+		}
+		v.Set(e.ValName, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
+
+// plainEnvSet reports whether the variable name is set in the environment or read by WithEnvFile.
+// The caller must hold mtx.
+func (r *Registry) plainEnvSet(name string) bool {
+	if _, ok := os.LookupEnv(name); ok {
+		return true
+	}
+	_, ok := r.envFile[name]
+	return ok
+}
+
+// checkRequired reports every Required binding of the entry that has neither a value nor a default.
+func (r *Registry) checkRequired(v *viper.Viper, entry *ConfigEntry) error {
+	prefix := r.entryEnvPrefix(entry)
+	var errs []error
+	for _, e := range entry.BindArray {
+		if e.Required && !isNotNullOrDefault(e.DefaultVal) && !v.IsSet(e.ValName) {
+			errs = append(errs, fmt.Errorf("required value %s is not set: set %s", e.ValName, envName(prefix, e.ValName)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// entryEnvPrefix returns the environment prefix applied to the entry.
+func (r *Registry) entryEnvPrefix(entry *ConfigEntry) string {
+	if entry.EnvPrefix != "" {
+		return entry.EnvPrefix
+	}
+	return r.envPrefix
+}
+
+// entryViper returns a new viper instance to load an entry from. It uses the entry environment
+// prefix and reads the config file set by WithConfigFile. Every entry gets its own instance,
+// so defaults, flags and overrides of identical keys in different entries never collide.
+func (r *Registry) entryViper(entry *ConfigEntry) (*viper.Viper, error) {
+	v := viper.New()
+	if prefix := r.entryEnvPrefix(entry); prefix != "" {
+		v.SetEnvPrefix(prefix)
+	}
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv()
+	if file := r.Viper().ConfigFileUsed(); file != "" {
+		v.SetConfigFile(file)
+		v.SetConfigType(r.configFileFormat)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,68 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_Isolation(t *testing.T) {
+	t.Setenv("HM_LOG_LEVEL", "debug")
+	t.Setenv("AUX_LOG_LEVEL", "warn")
+
+	hm, aux := NewRegistry(), NewRegistry()
+	hmConf, auxConf := &LevelConfig{}, &LevelConfig{}
+	require.NoError(t, hm.Register("log", ConfigEntry{Config: hmConf, BindArray: []BindValue{{ValName: "log_level"}}}))
+	require.NoError(t, aux.Register("log", ConfigEntry{Config: auxConf, BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}}}))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs[0] = hm.Load(WithSetEnvPrefix("HM"))
+	}()
+	go func() {
+		defer wg.Done()
+		errs[1] = aux.Load(WithSetEnvPrefix("AUX"))
+	}()
+	wg.Wait()
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+
+	assert.Equal(t, "debug", hmConf.Level)
+	assert.Equal(t, "warn", auxConf.Level)
+
+	got, err := GetFrom[LevelConfig](aux, "log")
+	require.NoError(t, err)
+	assert.Same(t, auxConf, got)
+
+	_, ok := GetConfig("log")
+	assert.False(t, ok, "expected registries to leave the default registry untouched")
+
+	require.NoError(t, hm.Unregister("log"))
+	assert.Empty(t, hm.List())
+	assert.Equal(t, []string{"log"}, aux.List())
+}
+
+func TestRegistry_Reset(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register("log", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+	}))
+	require.NoError(t, r.Load())
+	require.NoError(t, r.SetValue("log", "log_level", "debug"))
+
+	r.Reset()
+	assert.Empty(t, r.List())
+
+	conf := &LevelConfig{}
+	require.NoError(t, r.Register("log", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info"}},
+	}))
+	require.NoError(t, r.Load())
+	assert.Equal(t, "info", conf.Level, "expected Reset to drop overrides")
+}
@@ -0,0 +1,57 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBindValue_Required(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := &SecretConfig{}
+	require.NoError(t, RegisterConfig("secret", ConfigEntry{
+		Config:    conf,
+		BindArray: []BindValue{{ValName: "db_uri", Required: true}, {ValName: "token", Required: true}},
+	}))
+	require.NoError(t, RegisterConfig("aux", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level", Required: true}},
+		EnvPrefix: "AUX",
+	}))
+
+	err := NewConfig(WithSetEnvPrefix("HM"))
+	require.Error(t, err, "expected missing required values to fail NewConfig")
+	assert.Contains(t, err.Error(), "required value db_uri is not set: set HM_DB_URI")
+	assert.Contains(t, err.Error(), "required value token is not set: set HM_TOKEN")
+	assert.Contains(t, err.Error(), "required value log_level is not set: set AUX_LOG_LEVEL")
+
+	t.Setenv("HM_DB_URI", "postgres://db")
+	t.Setenv("HM_TOKEN", "token")
+	t.Setenv("AUX_LOG_LEVEL", "debug")
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")))
+	assert.Equal(t, "postgres://db", conf.DbUri)
+}
+
+func TestBindValue_RequiredWithDefault(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level", DefaultVal: "info", Required: true}},
+	}))
+
+	assert.NoError(t, NewConfig(), "expected a default to satisfy a required value")
+}
+
+func TestBindValue_RequiredFromFile(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level", Required: true}},
+	}))
+
+	path := writeConfigFile(t, "config.yaml", "log_level: debug\n")
+	assert.NoError(t, NewConfig(WithConfigFile(path, "")), "expected a file value to satisfy a required value")
+}
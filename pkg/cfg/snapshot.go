@@ -0,0 +1,124 @@
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Change is a field whose value differs between two snapshots.
+type Change struct {
+	Entry string // Registered config name
+	Field string // mapstructure key, dotted for nested structs, e.g. "db.uri"
+	Old   any    // Value in the first snapshot, nil if absent
+	New   any    // Value in the second snapshot, nil if absent
+}
+
+// secretValue holds the value of a secret field in a snapshot. It prints as "***",
+// so snapshots are safe to log, while Diff can still tell whether the value changed.
+type secretValue struct {
+	value any
+}
+
+// String implements fmt.Stringer.
+func (secretValue) String() string {
+	return redactedValue
+}
+
+// MarshalJSON implements json.Marshaler.
+func (secretValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedValue)
+}
+
+// Snapshot captures the current values of all registered configs keyed like DumpConfigs: by config
+// name and then by lowercased mapstructure key, with squashed structs flattened and nested structs
+// as nested maps. Values, time.Time included, are copies, so later reloads
+// do not alter a snapshot. Secret fields, as masked by DumpConfigs, print as "***".
+//
+// Example usage:
+//
+//	before := cfg.Snapshot()
+//	// ... config file changes and Watch reloads it
+//	for _, c := range cfg.Diff(before, cfg.Snapshot()) {
+//		logger.Info("config changed", "entry", c.Entry, "field", c.Field, "old", c.Old, "new", c.New)
+//	}
+func Snapshot() map[string]map[string]any {
+	return defaultRegistry.Snapshot()
+}
+
+// Snapshot captures the current values of all configs registered in r. See the package-level Snapshot.
+func (r *Registry) Snapshot() map[string]map[string]any {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	snap := make(map[string]map[string]any, len(r.entries))
+	for name, entry := range r.entries {
+		snap[name] = dumpStruct(reflect.ValueOf(entry.Config).Elem(), func(v reflect.Value) any {
+			return secretValue{value: deepCopy(v).Interface()}
+		})
+	}
+	return snap
+}
+
+// Diff lists the fields whose values differ between snapshots a and b, ordered by entry and field.
+// Entries present in only one snapshot report all their fields. Secret fields are reported
+// with "***" as old and new values.
+func Diff(a, b map[string]map[string]any) []Change {
+	var changes []Change
+	for _, entry := range unionKeys(a, b) {
+		changes = diffFields(changes, entry, "", a[entry], b[entry])
+	}
+	return changes
+}
+
+// diffFields appends the changes between the fields of a and b, prefixing field names with prefix.
+func diffFields(changes []Change, entry, prefix string, a, b map[string]any) []Change {
+	for _, key := range unionKeys(a, b) {
+		field := prefix + key
+		oldVal, oldOk := a[key]
+		newVal, newOk := b[key]
+
+		oldMap, oldIsMap := oldVal.(map[string]any)
+		newMap, newIsMap := newVal.(map[string]any)
+		if (oldIsMap || !oldOk) && (newIsMap || !newOk) {
+			changes = diffFields(changes, entry, field+".", oldMap, newMap)
+			continue
+		}
+		if oldOk && newOk && reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		changes = append(changes, Change{Entry: entry, Field: field, Old: maskSecret(oldVal), New: maskSecret(newVal)})
+	}
+	return changes
+}
+
+// maskSecret returns "***" for a secret value and v otherwise.
+func maskSecret(v any) any {
+	if _, ok := v.(secretValue); ok {
+		return redactedValue
+	}
+	return v
+}
+
+// unionKeys returns the keys present in a or b in sorted order.
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		seen[k] = struct{}{}
+	}
+	for k := range b {
+		seen[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String implements fmt.Stringer, for audit log lines.
+func (c Change) String() string {
+	return fmt.Sprintf("%s.%s: %v -> %v", c.Entry, c.Field, c.Old, c.New)
+}
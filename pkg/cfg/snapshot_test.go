@@ -0,0 +1,85 @@
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type SnapshotConfig struct {
+	Level   string   `mapstructure:"log_level"`
+	Proxies []string `mapstructure:"trusted_proxies"`
+	DB      struct {
+		URI  string `mapstructure:"uri"`
+		Pool int    `mapstructure:"pool"`
+	} `mapstructure:"db"`
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.1")
+	t.Setenv("DB_URI", "postgres://old")
+	require.NoError(t, RegisterConfig("svc", ConfigEntry{
+		Config: &SnapshotConfig{},
+		BindArray: []BindValue{
+			{ValName: "log_level"},
+			{ValName: "trusted_proxies"},
+			{ValName: "db.uri"},
+			{ValName: "db.pool", DefaultVal: 4},
+		},
+	}))
+	require.NoError(t, NewConfig())
+
+	before := Snapshot()
+	assert.Equal(t, `{"svc":{"db":{"pool":4,"uri":"***"},"log_level":"info","trusted_proxies":["10.0.0.1"]}}`,
+		mustJSON(t, before), "expected secrets to be masked in snapshots")
+	assert.Equal(t, "***", fmt.Sprint(before["svc"]["db"].(map[string]any)["uri"]))
+	assert.Empty(t, Diff(before, Snapshot()))
+
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.1,10.0.0.2")
+	t.Setenv("DB_URI", "postgres://new")
+	require.NoError(t, NewConfig())
+	require.NoError(t, RegisterConfig("late", ConfigEntry{Config: &LevelConfig{}}))
+
+	changes := Diff(before, Snapshot())
+	assert.Equal(t, []Change{
+		{Entry: "late", Field: "log_level", Old: nil, New: ""},
+		{Entry: "svc", Field: "db.uri", Old: "***", New: "***"},
+		{Entry: "svc", Field: "log_level", Old: "info", New: "debug"},
+		{Entry: "svc", Field: "trusted_proxies", Old: []string{"10.0.0.1"}, New: []string{"10.0.0.1", "10.0.0.2"}},
+	}, changes)
+	assert.Equal(t, "svc.log_level: info -> debug", changes[2].String())
+}
+
+// TestDiff_SquashAndTime ensures that squashed fields are diffed under their own keys
+// and that time.Time changes are reported.
+func TestDiff_SquashAndTime(t *testing.T) {
+	defer Reset()
+	conf := &struct {
+		LevelConfig `mapstructure:",squash"`
+		Started     time.Time `mapstructure:"started"`
+	}{LevelConfig: LevelConfig{Level: "info"}, Started: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	require.NoError(t, RegisterConfig("svc", ConfigEntry{Config: conf}))
+
+	before := Snapshot()
+	conf.Level = "debug"
+	conf.Started = conf.Started.Add(time.Hour)
+
+	assert.Equal(t, []Change{
+		{Entry: "svc", Field: "log_level", Old: "info", New: "debug"},
+		{Entry: "svc", Field: "started", Old: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), New: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)},
+	}, Diff(before, Snapshot()))
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -0,0 +1,104 @@
+package cfg
+
+import (
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// WithStrictEnv makes NewConfig fail if an environment variable, or a variable read by WithEnvFile,
+// starts with the prefix set by WithSetEnvPrefix, or with the EnvPrefix of an entry,
+// but matches no registered BindValue.
+// This catches typos like HM_LOG_LEVLE that would otherwise be silently ignored.
+// Variables named in allowed are never reported. The check runs after all entries load successfully.
+//
+// Example Usage:
+//
+//	err := cfg.NewConfig(cfg.WithSetEnvPrefix("HM"), cfg.WithStrictEnv("HM_DEBUG_PPROF"))
+//	if err != nil {
+//	    fmt.Printf("Error initializing configs: %v\n", err)
+//	}
+func WithStrictEnv(allowed ...string) ViperOption {
+	return func(r *Registry) error {
+		r.strictEnv.Store(true)
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		if r.allowedEnv == nil {
+			r.allowedEnv = make(map[string]struct{}, len(allowed))
+		}
+		for _, name := range allowed {
+			r.allowedEnv[strings.ToUpper(name)] = struct{}{}
+		}
+		return nil
+	}
+}
+
+// UnknownEnvVars returns the prefixed environment variables matching no registered BindValue
+// as of the last NewConfig call, in sorted order. It is filled with or without WithStrictEnv,
+// so callers can log the variables instead of failing.
+func UnknownEnvVars() []string {
+	return defaultRegistry.UnknownEnvVars()
+}
+
+// UnknownEnvVars returns the prefixed environment variables matching no binding of r.
+// See the package-level UnknownEnvVars.
+func (r *Registry) UnknownEnvVars() []string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return slices.Clone(r.unknownEnv)
+}
+
+// scanUnknownEnv returns the environment variables starting with a prefix in use that match
+// no binding, no _FILE secret of a binding when WithFileSecrets is set, and no allowed name. The caller must hold mtx.
+func (r *Registry) scanUnknownEnv() []string {
+	prefixes := make(map[string]struct{})
+	known := make(map[string]struct{})
+	for _, entry := range r.entries {
+		prefix := r.entryEnvPrefix(&entry)
+		if prefix == "" {
+			continue
+		}
+		prefixes[strings.ToUpper(prefix)+"_"] = struct{}{}
+		for _, e := range entry.BindArray {
+			name := envName(prefix, e.ValName)
+			known[name] = struct{}{}
+			for _, alias := range e.Aliases {
+				known[envName(prefix, alias)] = struct{}{}
+			}
+			if r.fileSecretsOnLoad.Load() {
+				known[name+"_FILE"] = struct{}{}
+			}
+		}
+	}
+	if r.envPrefix != "" {
+		prefixes[strings.ToUpper(r.envPrefix)+"_"] = struct{}{}
+	}
+
+	names := make(map[string]struct{}, len(r.envFile))
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		names[name] = struct{}{}
+	}
+	for name := range r.envFile {
+		names[name] = struct{}{}
+	}
+
+	var unknown []string
+	for name := range names {
+		if _, ok := known[name]; ok {
+			continue
+		}
+		if _, ok := r.allowedEnv[name]; ok {
+			continue
+		}
+		for prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				unknown = append(unknown, name)
+				break
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
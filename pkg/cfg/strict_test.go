@@ -0,0 +1,53 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func registerStrictConfigs(t *testing.T) {
+	t.Helper()
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level"}},
+	}))
+	require.NoError(t, RegisterConfig("aux", ConfigEntry{
+		Config:    &LevelConfig{},
+		BindArray: []BindValue{{ValName: "log_level"}},
+		EnvPrefix: "AUX",
+	}))
+}
+
+func TestWithStrictEnv(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("HM_LOG_LEVEL", "debug")
+	t.Setenv("HM_LOG_LEVLE", "debug")
+	t.Setenv("AUX_LOG_FORMAT", "json")
+	t.Setenv("HM_DEBUG_PPROF", "1")
+	t.Setenv("HMX_OTHER", "1")
+	registerStrictConfigs(t)
+
+	err := NewConfig(WithSetEnvPrefix("HM"), WithStrictEnv())
+	assert.EqualError(t, err, "unknown environment variables: AUX_LOG_FORMAT, HM_DEBUG_PPROF, HM_LOG_LEVLE")
+	assert.Equal(t, []string{"AUX_LOG_FORMAT", "HM_DEBUG_PPROF", "HM_LOG_LEVLE"}, UnknownEnvVars())
+
+	err = NewConfig(WithSetEnvPrefix("HM"), WithStrictEnv("HM_DEBUG_PPROF", "hm_log_levle", "AUX_LOG_FORMAT"))
+	assert.NoError(t, err, "expected allowed variables not to be reported")
+	assert.Empty(t, UnknownEnvVars())
+}
+
+func TestUnknownEnvVars_WithoutStrictEnv(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	t.Setenv("HM_LOG_LEVLE", "debug")
+	t.Setenv("HM_LOG_LEVEL_FILE", "/run/secrets/level")
+	registerStrictConfigs(t)
+
+	require.NoError(t, NewConfig(WithSetEnvPrefix("HM")), "expected unknown variables to be reported only")
+	assert.Equal(t, []string{"HM_LOG_LEVEL_FILE", "HM_LOG_LEVLE"}, UnknownEnvVars())
+
+	Reset()
+	assert.Empty(t, UnknownEnvVars())
+}
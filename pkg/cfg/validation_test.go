@@ -0,0 +1,62 @@
+package cfg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type RangeConfig struct {
+	Port  int    `mapstructure:"port" validate:"min=1,max=65535"`
+	Level string `mapstructure:"level" validate:"oneof=debug info"`
+}
+
+func TestWithValidation(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	conf := registerTestConfig(t)
+	require.NoError(t, RegisterConfig("untagged", ConfigEntry{
+		Config:    &OtherConfig{},
+		BindArray: []BindValue{{ValName: "field3"}},
+	}))
+
+	require.NoError(t, NewConfig(WithValidation()), "expected valid configs to pass validation")
+	assert.Equal(t, "default1", conf.Field1)
+}
+
+func TestWithValidation_AggregatesFailures(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("range", ConfigEntry{
+		Config: &RangeConfig{},
+		BindArray: []BindValue{
+			{ValName: "port", DefaultVal: 70000},
+			{ValName: "level", DefaultVal: "trace"},
+		},
+	}))
+	require.NoError(t, RegisterConfig("test", ConfigEntry{
+		Config:    &TestConfig{},
+		BindArray: []BindValue{{ValName: "field2"}},
+	}))
+
+	err := NewConfig(WithValidation())
+	require.Error(t, err, "expected validation errors")
+	msg := err.Error()
+	assert.Contains(t, msg, "config 'range': validation failed")
+	assert.Contains(t, msg, "Field 'Port'")
+	assert.Contains(t, msg, "Field 'Level'")
+	assert.Contains(t, msg, "config 'test': validation failed")
+	assert.Contains(t, msg, "Field 'Field1'")
+}
+
+func TestNewConfig_WithoutValidation(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	require.NoError(t, RegisterConfig("test", ConfigEntry{
+		Config:    &TestConfig{},
+		BindArray: []BindValue{{ValName: "field2"}},
+	}))
+
+	require.Error(t, NewConfig(WithValidation()))
+	assert.NoError(t, NewConfig(), "expected validation to be skipped without WithValidation")
+}
@@ -0,0 +1,169 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"github.com/KennyMacCormik/HerdMaster/pkg/val"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"reflect"
+)
+
+// Watch reloads all registered configs whenever the file set by WithConfigFile changes,
+// until ctx is done. Each entry is unmarshalled into a temporary struct and validated with the
+// val package. Entries whose values actually changed are copied into the registered struct under
+// the write lock, like NewConfig does, and onChange is called for each of them with that struct.
+// Pointers to the registered config therefore observe the reloaded values.
+//
+// Reload failures keep the previous values of the failing entries and are sent to the
+// returned channel, which is closed when ctx is done. Errors are dropped while the channel is full.
+// Watch returns an error if no config file is in use or if another Watch call is active.
+//
+// Example usage:
+//
+//	errs, err := cfg.Watch(ctx, func(name string, conf any) {
+//		if name == "log" {
+//			lvl.Set(conf.(*LoggingConfig).Level)
+//		}
+//	})
+//	if err != nil {
+//		// Handle error
+//	}
+//	go func() {
+//		for err := range errs {
+//			logger.Error("config reload failed", "error", err)
+//		}
+//	}()
+func Watch(ctx context.Context, onChange func(name string, cfg any)) (<-chan error, error) {
+	return defaultRegistry.Watch(ctx, onChange)
+}
+
+// Watch reloads all configs registered in r whenever its config file changes. See the package-level Watch.
+func (r *Registry) Watch(ctx context.Context, onChange func(name string, cfg any)) (<-chan error, error) {
+	if r.Viper().ConfigFileUsed() == "" {
+		return nil, fmt.Errorf("watch requires a config file set by WithConfigFile")
+	}
+
+	r.watchMtx.Lock()
+	defer r.watchMtx.Unlock()
+	if r.watchEvents != nil {
+		return nil, fmt.Errorf("config is already being watched")
+	}
+	events := make(chan struct{}, 1)
+	r.watchEvents = events
+
+	if v := r.Viper(); r.watchedViper != v {
+		r.watchedViper = v
+		v.OnConfigChange(func(fsnotify.Event) { r.notifyWatch() })
+		v.WatchConfig()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer r.stopWatch(events)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-events:
+				if err := r.reloadConfigs(onChange); err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+			}
+		}
+	}()
+	return errCh, nil
+}
+
+// notifyWatch signals the active Watch call, if any, without blocking the viper watcher.
+func (r *Registry) notifyWatch() {
+	r.watchMtx.Lock()
+	defer r.watchMtx.Unlock()
+	if r.watchEvents == nil {
+		return
+	}
+	select {
+	case r.watchEvents <- struct{}{}:
+	default:
+		// a reload is already pending and will read the latest file
+	}
+}
+
+// stopWatch detaches the Watch call owning events, so Watch can be called again.
+func (r *Registry) stopWatch(events chan struct{}) {
+	r.watchMtx.Lock()
+	defer r.watchMtx.Unlock()
+	if r.watchEvents == events {
+		r.watchEvents = nil
+	}
+}
+
+// reloadConfigs loads and validates every registered entry into a temporary struct, updates the
+// entries that changed in place, and calls onChange for them after releasing the lock.
+func (r *Registry) reloadConfigs(onChange func(name string, cfg any)) error {
+	// viper logs and ignores files it cannot parse, so check the file separately
+	file := r.Viper().ConfigFileUsed()
+	probe := viper.New()
+	probe.SetConfigFile(file)
+	probe.SetConfigType(r.configFileFormat)
+	if err := probe.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", file, err)
+	}
+
+	changed, errs := r.reloadEntriesWithLock()
+	if onChange != nil {
+		for _, c := range changed {
+			onChange(c.name, c.config)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// changedEntry is a config updated by a reload.
+type changedEntry struct {
+	name   string
+	config any
+}
+
+// reloadEntriesWithLock reloads every registered entry under the write lock and returns
+// the entries that changed in name order, together with the entries that failed.
+func (r *Registry) reloadEntriesWithLock() ([]changedEntry, ConfigErrors) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	names := r.sortedEntryNames()
+
+	var changed []changedEntry
+	var errs ConfigErrors
+	for _, name := range names {
+		entry := r.entries[name]
+		registered := entry.Config
+		current := reflect.ValueOf(registered)
+		entry.Config = reflect.New(current.Elem().Type()).Interface()
+
+		sources, err := r.bindActualValue(name, &entry)
+		if err != nil {
+			errs = append(errs, &EntryError{Name: name, Err: fmt.Errorf("failed to bind: %w", err)})
+			continue
+		}
+		if err := val.GetValidator().ValidateStruct(entry.Config); err != nil {
+			errs = append(errs, &EntryError{Name: name, Err: fmt.Errorf("validation failed: %w", err)})
+			continue
+		}
+		// the same values may now come from another source
+		r.sources[name] = sources
+		if reflect.DeepEqual(current.Elem().Interface(), reflect.ValueOf(entry.Config).Elem().Interface()) {
+			continue
+		}
+		storeInPlace(registered, entry.Config)
+		changed = append(changed, changedEntry{name: name, config: registered})
+	}
+	return changed, errs
+}
@@ -0,0 +1,115 @@
+package cfg
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const watchTimeout = 5 * time.Second
+
+type WatchedConfig struct {
+	Level string `mapstructure:"log_level" validate:"oneof=debug info warn error"`
+}
+
+// replaceFile atomically replaces the file at path, so the watcher never reads a partial write.
+func replaceFile(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := filepath.Join(t.TempDir(), filepath.Base(path))
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+// waitForError waits for an error containing msg on errs.
+func waitForError(t *testing.T, errs <-chan error, msg string) {
+	t.Helper()
+	timeout := time.After(watchTimeout)
+	for {
+		select {
+		case err := <-errs:
+			if err != nil && strings.Contains(err.Error(), msg) {
+				return
+			}
+		case <-timeout:
+			require.Failf(t, "timed out", "no reload error containing %q", msg)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+	registered := &WatchedConfig{}
+	require.NoError(t, RegisterConfig("log", ConfigEntry{
+		Config:    registered,
+		BindArray: []BindValue{{ValName: "log_level"}},
+	}))
+	registerTestConfig(t)
+
+	path := writeConfigFile(t, "config.yaml", "log_level: info\n")
+	require.NoError(t, NewConfig(WithConfigFile(path, "yaml")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan string, 10)
+	errs, err := Watch(ctx, func(name string, conf any) {
+		changes <- name + "=" + conf.(*WatchedConfig).Level
+	})
+	require.NoError(t, err)
+
+	_, err = Watch(ctx, nil)
+	assert.ErrorContains(t, err, "already being watched")
+
+	// a valid change replaces only the changed entry
+	replaceFile(t, path, "log_level: debug\n")
+	select {
+	case change := <-changes:
+		assert.Equal(t, "log=debug", change)
+	case <-time.After(watchTimeout):
+		require.Fail(t, "timed out waiting for the change callback")
+	}
+	current, err := Get[WatchedConfig]("log")
+	require.NoError(t, err)
+	assert.Equal(t, "debug", current.Level)
+	assert.Same(t, registered, current, "expected the registered struct to be kept")
+	assert.Equal(t, "debug", registered.Level, "expected the registered struct to be updated in place")
+
+	// an invalid value keeps the previous config
+	replaceFile(t, path, "log_level: verbose\n")
+	waitForError(t, errs, "config 'log': validation failed")
+	current, err = Get[WatchedConfig]("log")
+	require.NoError(t, err)
+	assert.Equal(t, "debug", current.Level)
+	assert.Equal(t, "debug", registered.Level, "expected a failed reload to leave the registered struct untouched")
+
+	// an unparsable file keeps the previous config
+	replaceFile(t, path, "log_level: [\n")
+	waitForError(t, errs, "failed to read config file")
+	current, err = Get[WatchedConfig]("log")
+	require.NoError(t, err)
+	assert.Equal(t, "debug", current.Level)
+	assert.Empty(t, changes, "expected no callbacks for failed reloads")
+
+	cancel()
+	require.Eventually(t, func() bool {
+		select {
+		case _, ok := <-errs:
+			return !ok
+		default:
+			return false
+		}
+	}, watchTimeout, 10*time.Millisecond, "expected the error channel to be closed")
+}
+
+func TestWatch_WithoutConfigFile(t *testing.T) {
+	defer Reset()
+	resetViper(t)
+
+	_, err := Watch(context.Background(), nil)
+	assert.ErrorContains(t, err, "requires a config file")
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// baggageLogPrefix prefixes baggage members exposed as log attributes by LogReq.
+const baggageLogPrefix = "baggage."
+
+// Baggage returns a middleware that extracts the W3C baggage header of the incoming request
+// and keeps only the members listed in allowedKeys. The filtered baggage replaces any baggage
+// in the request context, so handlers see only allowed members and outbound propagation
+// never forwards unknown keys. LogReq exposes the kept members as log attributes.
+//
+// Example usage:
+//
+//	router.Use(middleware.Baggage("tenant_id", "experiment"))
+func Baggage(allowedKeys ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(allowedKeys))
+	for _, k := range allowedKeys {
+		allowed[k] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		// extract baggage only, so the active span in the request context is left untouched
+		ctx := propagation.Baggage{}.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		members := make([]baggage.Member, 0, len(allowed))
+		for _, m := range baggage.FromContext(ctx).Members() {
+			if _, ok := allowed[m.Key()]; ok {
+				members = append(members, m)
+			}
+		}
+		// members were parsed from a valid baggage, so they form a valid one again
+		filtered, _ := baggage.New(members...)
+
+		c.Request = c.Request.WithContext(baggage.ContextWithBaggage(ctx, filtered))
+		c.Next()
+	}
+}
+
+// baggageLogAttrs returns the baggage members in the request context as slog key-value pairs.
+func baggageLogAttrs(c *gin.Context) []any {
+	members := baggage.FromContext(c.Request.Context()).Members()
+	attrs := make([]any, 0, 2*len(members))
+	for _, m := range members {
+		attrs = append(attrs, baggageLogPrefix+m.Key(), m.Value())
+	}
+	return attrs
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const baggageHeader = "baggage"
+
+func TestBaggage_RoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// downstream service recording the propagated headers
+	var outbound http.Header
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outbound = r.Header.Clone()
+	}))
+	defer downstream.Close()
+
+	router := gin.New()
+	router.Use(Baggage("tenant_id", "experiment"))
+	router.GET("/", func(c *gin.Context) {
+		bag := baggage.FromContext(c.Request.Context())
+		assert.Equal(t, "acme", bag.Member("tenant_id").Value())
+		assert.Equal(t, "blue", bag.Member("experiment").Value())
+		assert.Empty(t, bag.Member("secret").Key(), "unknown keys should not reach handlers")
+
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, downstream.URL, nil)
+		propagation.Baggage{}.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(baggageHeader, "tenant_id=acme,secret=hunter2,experiment=blue")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, outbound)
+	out, err := baggage.Parse(outbound.Get(baggageHeader))
+	require.NoError(t, err)
+	assert.Equal(t, "acme", out.Member("tenant_id").Value())
+	assert.Equal(t, "blue", out.Member("experiment").Value())
+	assert.Equal(t, 2, out.Len(), "only allowed keys should be propagated outbound")
+}
+
+func TestBaggage_NoHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Baggage("tenant_id"))
+	router.GET("/", func(c *gin.Context) {
+		assert.Zero(t, baggage.FromContext(c.Request.Context()).Len())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBaggage_KeepsActiveSpan(t *testing.T) {
+	newSpanRecorder(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var spanCtx trace.SpanContext
+	router.Use(func(c *gin.Context) {
+		span, end := StartGinSpan(c, "test tracer", "outer")
+		defer end()
+		spanCtx = span.SpanContext()
+		c.Next()
+	}, Baggage("tenant_id"))
+	router.GET("/", func(c *gin.Context) {
+		assert.Equal(t, spanCtx, trace.SpanContextFromContext(c.Request.Context()),
+			"baggage extraction should not replace the active span")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(baggageHeader, "tenant_id=acme")
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLogReq_BaggageAttributes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := &bytes.Buffer{}
+	lg := slog.New(slog.NewTextHandler(buf, nil))
+
+	router := gin.New()
+	router.Use(Baggage("tenant_id"))
+	router.GET("/", func(c *gin.Context) {
+		LogReq(c, "uuid", lg, false).Info("handled")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(baggageHeader, "tenant_id=acme,secret=hunter2")
+	router.ServeHTTP(w, req)
+
+	assert.Contains(t, buf.String(), "baggage.tenant_id=acme")
+	assert.NotContains(t, buf.String(), "hunter2")
+}
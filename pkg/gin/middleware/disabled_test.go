@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	hmotel "github.com/KennyMacCormik/HerdMaster/pkg/otel"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// setTracingEnabled overrides the tracing flag for the duration of the test.
+func setTracingEnabled(tb testing.TB, enabled bool) {
+	tb.Helper()
+	prev := hmotel.Enabled()
+	hmotel.SetEnabled(enabled)
+	tb.Cleanup(func() { hmotel.SetEnabled(prev) })
+}
+
+func newDisabledTestRouter(maxWait int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	rl := NewRateLimiter(1, maxWait, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), rl.GetRateLimiter())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestMiddlewares_TelemetryDisabled(t *testing.T) {
+	sr := newSpanRecorder(t)
+	setTracingEnabled(t, false)
+
+	tests := []struct {
+		name     string
+		maxWait  int
+		reqID    string
+		wantCode int
+	}{
+		{"accepted with generated request ID", 10, "", http.StatusOK},
+		{"accepted with supplied request ID", 10, "req-1", http.StatusOK},
+		{"rejected", 1, "req-2", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if tt.reqID != "" {
+				req.Header.Set(RequestIDKey, tt.reqID)
+			}
+			newDisabledTestRouter(tt.maxWait).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.reqID != "" {
+				assert.Equal(t, tt.reqID, w.Header().Get(RequestIDKey))
+			} else {
+				assert.NotEmpty(t, w.Header().Get(RequestIDKey))
+			}
+		})
+	}
+	require.Empty(t, sr.Ended(), "no spans should be started while tracing is disabled")
+}
+
+func benchmarkMiddlewares(b *testing.B) {
+	router := newDisabledTestRouter(10)
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDKey, "bench")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+// BenchmarkMiddlewares_NoopProvider measures the former behavior: spans are started
+// against a no-op global provider even though nothing is exported.
+func BenchmarkMiddlewares_NoopProvider(b *testing.B) {
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	b.Cleanup(func() { otel.SetTracerProvider(prev) })
+	setTracingEnabled(b, true)
+	benchmarkMiddlewares(b)
+}
+
+// BenchmarkMiddlewares_TelemetryDisabled measures the disabled path.
+func BenchmarkMiddlewares_TelemetryDisabled(b *testing.B) {
+	setTracingEnabled(b, false)
+	benchmarkMiddlewares(b)
+}
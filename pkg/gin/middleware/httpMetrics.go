@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"strconv"
+	"time"
+)
+
+// UnmatchedRoute is the route label of requests that matched no registered route.
+const UnmatchedRoute = "unmatched"
+
+// statusClassKey is the attribute carrying the response status class, e.g. "2xx".
+const statusClassKey = attribute.Key("http.response.status_class")
+
+// DefaultDurationBuckets are the histogram boundaries, in seconds, used when none are configured.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HTTPMetricsOption represents a functional option for configuring OtelHTTPMetrics.
+type HTTPMetricsOption func(*httpMetricsConfig)
+
+type httpMetricsConfig struct {
+	buckets []float64
+}
+
+// WithDurationBuckets sets the explicit histogram bucket boundaries, in seconds.
+// An empty slice keeps DefaultDurationBuckets.
+func WithDurationBuckets(buckets []float64) HTTPMetricsOption {
+	return func(cfg *httpMetricsConfig) {
+		if len(buckets) > 0 {
+			cfg.buckets = buckets
+		}
+	}
+}
+
+// OtelHTTPMetrics returns a middleware recording the duration of every request in the
+// http.server.request.duration histogram on meter. Observations are labeled with the gin route
+// template (e.g. /dog/:id), the request method and the response status class. Requests matching
+// no route share the UnmatchedRoute label to bound cardinality.
+//
+// Observations are recorded with the request context, so a sampled span in it is attached as an
+// exemplar when the SDK exemplar support is enabled (OTEL_GO_X_EXEMPLAR=true in SDK v1.29).
+//
+// Example usage:
+//
+//	mw, err := middleware.OtelHTTPMetrics(otel.Meter("herdmaster"),
+//	    middleware.WithDurationBuckets(conf.MetricsBuckets))
+//	if err != nil {
+//	    return err
+//	}
+//	router.Use(mw)
+func OtelHTTPMetrics(meter metric.Meter, opts ...HTTPMetricsOption) (gin.HandlerFunc, error) {
+	cfg := httpMetricsConfig{buckets: DefaultDurationBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(cfg.buckets...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request duration histogram: %w", err)
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		elapsed := time.Since(start).Seconds()
+
+		route := c.FullPath()
+		if route == "" {
+			route = UnmatchedRoute
+		}
+		// c.Request carries the spans started down the chain, which provide the exemplar
+		duration.Record(c.Request.Context(), elapsed, metric.WithAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPRequestMethodKey.String(c.Request.Method),
+			statusClassKey.String(statusClass(c.Writer.Status())),
+		))
+	}, nil
+}
+
+// statusClass returns the class of an HTTP status code, e.g. "4xx" for 404.
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOtelHTTPMetrics(t *testing.T) {
+	t.Setenv("OTEL_GO_X_EXEMPLAR", "true")
+	sr := newSpanRecorder(t)
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+
+	mw, err := OtelHTTPMetrics(mp.Meter("test"), WithDurationBuckets([]float64{0.05, 1}))
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mw, RequestIDMiddleware())
+	router.GET("/dog/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/slow/:id", func(c *gin.Context) {
+		time.Sleep(60 * time.Millisecond)
+		c.Status(http.StatusCreated)
+	})
+
+	for _, target := range []string{"/dog/1", "/dog/42", "/slow/1", "/missing", "/other/path"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		router.ServeHTTP(w, req)
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "http.server.request.duration", m.Name)
+	assert.Equal(t, "s", m.Unit)
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok, "expected a float64 histogram, got %T", m.Data)
+
+	want := map[attribute.Set][]uint64{
+		attribute.NewSet(semconv.HTTPRoute("/dog/:id"), semconv.HTTPRequestMethodKey.String("GET"),
+			statusClassKey.String("2xx")): {2, 0, 0},
+		attribute.NewSet(semconv.HTTPRoute("/slow/:id"), semconv.HTTPRequestMethodKey.String("GET"),
+			statusClassKey.String("2xx")): {0, 1, 0},
+		attribute.NewSet(semconv.HTTPRoute(UnmatchedRoute), semconv.HTTPRequestMethodKey.String("GET"),
+			statusClassKey.String("4xx")): {2, 0, 0},
+	}
+	got := make(map[attribute.Set][]uint64, len(hist.DataPoints))
+	for _, dp := range hist.DataPoints {
+		assert.Equal(t, []float64{0.05, 1}, dp.Bounds)
+		got[dp.Attributes] = dp.BucketCounts
+	}
+	assert.Equal(t, want, got)
+
+	traceIDs := make(map[string]struct{})
+	for _, s := range sr.Ended() {
+		traceIDs[s.SpanContext().TraceID().String()] = struct{}{}
+	}
+	for _, dp := range hist.DataPoints {
+		require.NotEmpty(t, dp.Exemplars, "every observation should carry an exemplar")
+		for _, e := range dp.Exemplars {
+			var id trace.TraceID
+			copy(id[:], e.TraceID)
+			assert.Contains(t, traceIDs, id.String(), "exemplar should reference a recorded trace")
+		}
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, "1xx", statusClass(http.StatusContinue))
+	assert.Equal(t, "2xx", statusClass(http.StatusNoContent))
+	assert.Equal(t, "4xx", statusClass(http.StatusNotFound))
+	assert.Equal(t, "5xx", statusClass(http.StatusServiceUnavailable))
+}
@@ -1,10 +1,16 @@
 package middleware
 
 import (
+	"github.com/KennyMacCormik/HerdMaster/pkg/log"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 	"log/slog"
 )
 
+// LogReq returns lg enriched with the request ID, method, path, any baggage members and,
+// when the active span is recording, its trace_id and span_id. The enriched logger is also
+// stored in the request context, so handlers can retrieve it with log.FromContext.
+// If withReqDump is true, it also logs the request at debug level.
 func LogReq(c *gin.Context, uuid string, lg *slog.Logger, withReqDump bool) *slog.Logger {
 	if withReqDump {
 		lg.Debug("request received",
@@ -19,5 +25,22 @@ func LogReq(c *gin.Context, uuid string, lg *slog.Logger, withReqDump bool) *slo
 			"UrlPath", c.Request.URL.Path,
 		)
 	}
-	return lg.With("UUID", uuid, "Method", c.Request.Method, "UrlPath", c.Request.URL.Path)
+	attrs := append([]any{"UUID", uuid, "Method", c.Request.Method, "UrlPath", c.Request.URL.Path}, baggageLogAttrs(c)...)
+	attrs = append(attrs, traceLogAttrs(c)...)
+	reqLg := lg.With(attrs...)
+	c.Request = c.Request.WithContext(log.IntoContext(c.Request.Context(), reqLg))
+	return reqLg
+}
+
+// traceLogAttrs returns the trace and span IDs of the recording span in the request context
+// as slog key-value pairs, or nothing if no span is recording.
+func traceLogAttrs(c *gin.Context) []any {
+	span := trace.SpanFromContext(c.Request.Context())
+	if !span.IsRecording() || !span.SpanContext().IsValid() {
+		return nil
+	}
+	return []any{
+		"trace_id", span.SpanContext().TraceID().String(),
+		"span_id", span.SpanContext().SpanID().String(),
+	}
 }
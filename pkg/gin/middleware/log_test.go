@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/KennyMacCormik/HerdMaster/pkg/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveWithHandlerLog serves a request through RequestIDMiddleware and the rate limiter,
+// and returns the JSON record emitted by the handler via log.FromContext.
+func serveWithHandlerLog(t *testing.T) map[string]any {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	buf := &bytes.Buffer{}
+	rl := NewRateLimiter(1, 10, 1, slog.New(slog.NewJSONHandler(buf, nil)))
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), rl.GetRateLimiter())
+	router.GET("/", func(c *gin.Context) {
+		log.FromContext(c.Request.Context()).Info("handler record")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		if rec["msg"] == "handler record" {
+			return rec
+		}
+	}
+	require.Fail(t, "handler record not found", buf.String())
+	return nil
+}
+
+func TestLogReq_TraceAttributes(t *testing.T) {
+	sr := newSpanRecorder(t)
+	rec := serveWithHandlerLog(t)
+
+	limiterSpan := spanByName(t, sr.Ended(), "rate limiting middleware")
+	assert.Equal(t, limiterSpan.SpanContext().TraceID().String(), rec["trace_id"])
+	assert.Equal(t, limiterSpan.SpanContext().SpanID().String(), rec["span_id"])
+	assert.NotEmpty(t, rec["UUID"], "handler records should inherit the request attributes")
+}
+
+func TestLogReq_NoRecordingSpan(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	rec := serveWithHandlerLog(t)
+
+	assert.NotContains(t, rec, "trace_id")
+	assert.NotContains(t, rec, "span_id")
+	assert.NotEmpty(t, rec["UUID"])
+}
@@ -3,7 +3,6 @@ package middleware
 import (
 	"errors"
 	"github.com/gin-gonic/gin"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"log/slog"
@@ -23,8 +22,8 @@ type RateLimiter struct {
 	lg      *slog.Logger
 	limiter chan struct{}
 
-	maxRunning, maxWait, retryAfter    int
-	running, total, timedOut, rejected atomic.Int32
+	maxRunning, maxWait, retryAfter              int
+	running, total, timedOut, rejected, accepted atomic.Int32
 }
 
 func NewRateLimiter(maxRunning, maxWait, retryAfter int, lg *slog.Logger) *RateLimiter {
@@ -39,16 +38,13 @@ func (rm *RateLimiter) GetRateLimiter() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rm.total.Add(1)
 		defer rm.total.Add(-1)
-		const (
-			traceName = "gin.middleware.GetRateLimiter"
-			spanName  = "rate limiting middleware"
-		)
 		// init trace
-		tracer := otel.Tracer(traceName)
-		ctx, span := tracer.Start(c.Request.Context(), spanName)
-		c.Request = c.Request.WithContext(ctx)
-		defer span.End()
+		span, end := StartGinSpan(c, "gin.middleware.GetRateLimiter", "rate limiting middleware")
+		defer end()
 		defer func(span trace.Span) {
+			if !span.IsRecording() {
+				return
+			}
 			span.SetAttributes(
 				attribute.Int("running", int(rm.running.Load())),
 				attribute.Int("total", int(rm.total.Load())),
@@ -59,39 +55,40 @@ func (rm *RateLimiter) GetRateLimiter() gin.HandlerFunc {
 		// init logger
 		uuid, err := GetRequestIDFromCtx(c)
 		if err != nil && errors.Is(err, &ErrTypeCastFailed{}) {
-			span.AddEvent(
-				"failed to get request ID from context",
-				trace.WithAttributes(attribute.String("error", err.Error())),
-			)
+			AddEventIfErr(span, "failed to get request ID from context", err)
 			rm.lg.Error("failed to get request ID from context", "error", err.Error())
-			c.AbortWithStatus(http.StatusInternalServerError)
+			RespondError(c, http.StatusInternalServerError, ErrCodeInternal,
+				http.StatusText(http.StatusInternalServerError), nil)
 			return
 		}
 		reqLg := LogReq(c, uuid, rm.lg, true)
-		// Log the Trace ID
-		reqLg.Info("request trace ID", "traceID", span.SpanContext().TraceID().String())
 
 		if err != nil {
-			span.AddEvent(
-				"fallback uuid used",
-				trace.WithAttributes(attribute.String("error", err.Error())),
-			)
-			span.SetAttributes(attribute.String("fallback requestID", uuid))
+			AddEventIfErr(span, "fallback uuid used", err)
+			if span.IsRecording() {
+				span.SetAttributes(attribute.String("fallback requestID", uuid))
+			}
 			reqLg.Warn("fallback uuid used", "error", err.Error())
 		}
 		// reject if too may goroutines
 		if rm.total.Load() >= int32(rm.maxWait) {
 			rm.rejected.Add(1)
-			span.AddEvent(
-				"too many total requests, rejecting request",
-				trace.WithAttributes(attribute.String("error", err.Error())),
-			)
+			if span.IsRecording() {
+				span.AddEvent(
+					"too many total requests, rejecting request",
+					trace.WithAttributes(
+						attribute.Int("total", int(rm.total.Load())),
+						attribute.Int("maxWait", rm.maxWait),
+					),
+				)
+			}
 			reqLg.Error("too many total requests, rejecting request",
 				"total", rm.total.Load(),
 				"maxWait", rm.maxWait,
 			)
 			c.Header("Retry-After", strconv.Itoa(rm.retryAfter))
-			c.AbortWithStatus(http.StatusTooManyRequests)
+			RespondError(c, http.StatusTooManyRequests, ErrCodeTooManyRequests,
+				"too many requests, retry later", nil)
 			return
 		}
 		// wait or run
@@ -105,7 +102,8 @@ func (rm *RateLimiter) GetRateLimiter() gin.HandlerFunc {
 			span.AddEvent("request's context expired before request was handled")
 			reqLg.Error("request's context expired before request was handled")
 			c.Header("Retry-After", strconv.Itoa(rm.retryAfter))
-			c.AbortWithStatus(http.StatusTooManyRequests)
+			RespondError(c, http.StatusTooManyRequests, ErrCodeTooManyRequests,
+				"request expired while waiting in queue, retry later", nil)
 		}
 	}
 }
@@ -126,7 +124,12 @@ func (rm *RateLimiter) GetTimedOutRequests() int {
 	return int(rm.timedOut.Load())
 }
 
+func (rm *RateLimiter) GetAcceptedRequests() int {
+	return int(rm.accepted.Load())
+}
+
 func (rm *RateLimiter) runReqWithSync(c *gin.Context, span trace.Span, reqLg *slog.Logger) {
+	rm.accepted.Add(1)
 	rm.running.Add(1)
 	defer rm.running.Add(-1)
 	defer func() { <-rm.limiter }()
@@ -135,13 +138,15 @@ func (rm *RateLimiter) runReqWithSync(c *gin.Context, span trace.Span, reqLg *sl
 	reqLg.Info("request accepted")
 	c.Next()
 	duration := time.Since(start)
-	span.AddEvent(
-		"request completed",
-		trace.WithAttributes(
-			attribute.Int("Status", c.Writer.Status()),
-			attribute.String("Duration", duration.String()),
-		),
-	)
+	if span.IsRecording() {
+		span.AddEvent(
+			"request completed",
+			trace.WithAttributes(
+				attribute.Int("Status", c.Writer.Status()),
+				attribute.String("Duration", duration.String()),
+			),
+		)
+	}
 	reqLg.Info("request completed",
 		"Status", c.Writer.Status(),
 		"Duration", duration,
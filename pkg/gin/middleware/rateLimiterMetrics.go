@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// rateLimiterNameKey is the attribute carrying the limiter name on every observation.
+const rateLimiterNameKey = attribute.Key("limiter")
+
+// RegisterOtelMetrics registers asynchronous instruments reporting the limiter state on meter.
+// Running and queued requests are reported as gauges, accepted, rejected and timed-out
+// requests as monotonic counters. Every observation carries the limiter name as an attribute.
+//
+// Example usage:
+//
+//	rl := middleware.NewRateLimiter(100, 1000, 1, lg)
+//	if err := rl.RegisterOtelMetrics(otel.Meter("herdmaster"), "api"); err != nil {
+//	    return err
+//	}
+func (rm *RateLimiter) RegisterOtelMetrics(meter metric.Meter, name string) error {
+	running, err := meter.Int64ObservableGauge("ratelimiter.requests.running",
+		metric.WithDescription("Requests currently handled by the rate limiter."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return fmt.Errorf("failed to create running requests gauge: %w", err)
+	}
+	queued, err := meter.Int64ObservableGauge("ratelimiter.requests.queued",
+		metric.WithDescription("Requests currently waiting for a free slot."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return fmt.Errorf("failed to create queued requests gauge: %w", err)
+	}
+	accepted, err := meter.Int64ObservableCounter("ratelimiter.requests.accepted",
+		metric.WithDescription("Requests accepted by the rate limiter."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return fmt.Errorf("failed to create accepted requests counter: %w", err)
+	}
+	rejected, err := meter.Int64ObservableCounter("ratelimiter.requests.rejected",
+		metric.WithDescription("Requests rejected because the queue was full."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return fmt.Errorf("failed to create rejected requests counter: %w", err)
+	}
+	timedOut, err := meter.Int64ObservableCounter("ratelimiter.requests.timed_out",
+		metric.WithDescription("Requests whose context expired while waiting in the queue."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return fmt.Errorf("failed to create timed out requests counter: %w", err)
+	}
+
+	attrs := metric.WithAttributes(rateLimiterNameKey.String(name))
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		run := int64(rm.GetRunningRequests())
+		o.ObserveInt64(running, run, attrs)
+		o.ObserveInt64(queued, max(int64(rm.GetTotalRequests())-run, 0), attrs)
+		o.ObserveInt64(accepted, int64(rm.GetAcceptedRequests()), attrs)
+		o.ObserveInt64(rejected, int64(rm.GetRejectedRequests()), attrs)
+		o.ObserveInt64(timedOut, int64(rm.GetTimedOutRequests()), attrs)
+		return nil
+	}, running, queued, accepted, rejected, timedOut)
+	if err != nil {
+		return fmt.Errorf("failed to register rate limiter metrics callback: %w", err)
+	}
+	return nil
+}
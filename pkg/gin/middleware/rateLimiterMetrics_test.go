@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// collectLimiterMetrics reads the current limiter observations, keyed by instrument name.
+func collectLimiterMetrics(t *testing.T, reader sdkmetric.Reader, name string) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	values := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			var points []metricdata.DataPoint[int64]
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				points = data.DataPoints
+			case metricdata.Sum[int64]:
+				assert.True(t, data.IsMonotonic, "%s should be monotonic", m.Name)
+				points = data.DataPoints
+			}
+			for _, p := range points {
+				v, ok := p.Attributes.Value(rateLimiterNameKey)
+				if ok && v == attribute.StringValue(name) {
+					values[m.Name] = p.Value
+				}
+			}
+		}
+	}
+	return values
+}
+
+func assertLimiterMetrics(t *testing.T, reader sdkmetric.Reader, rl *RateLimiter) {
+	t.Helper()
+	assert.Equal(t, map[string]int64{
+		"ratelimiter.requests.running":   int64(rl.GetRunningRequests()),
+		"ratelimiter.requests.queued":    int64(rl.GetTotalRequests() - rl.GetRunningRequests()),
+		"ratelimiter.requests.accepted":  int64(rl.GetAcceptedRequests()),
+		"ratelimiter.requests.rejected":  int64(rl.GetRejectedRequests()),
+		"ratelimiter.requests.timed_out": int64(rl.GetTimedOutRequests()),
+	}, collectLimiterMetrics(t, reader, "test"))
+}
+
+func TestRateLimiter_RegisterOtelMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+
+	rl := NewRateLimiter(1, 3, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, rl.RegisterOtelMetrics(mp.Meter("test"), "test"))
+
+	release := make(chan struct{})
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), rl.GetRateLimiter())
+	router.GET("/", func(c *gin.Context) {
+		<-release
+		c.Status(http.StatusOK)
+	})
+	serve := func(ctx context.Context) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assertLimiterMetrics(t, reader, rl)
+
+	// occupy the only slot
+	done := make(chan struct{}, 2)
+	go func() { serve(context.Background()); done <- struct{}{} }()
+	require.Eventually(t, func() bool { return rl.GetRunningRequests() == 1 }, time.Second, time.Millisecond)
+
+	// expired context while waiting for the slot
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, http.StatusTooManyRequests, serve(ctx).Code)
+
+	// queue one request, then overflow the queue
+	go func() { serve(context.Background()); done <- struct{}{} }()
+	require.Eventually(t, func() bool { return rl.GetTotalRequests() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, http.StatusTooManyRequests, serve(context.Background()).Code)
+
+	got := collectLimiterMetrics(t, reader, "test")
+	assert.Equal(t, int64(1), got["ratelimiter.requests.running"])
+	assert.Equal(t, int64(1), got["ratelimiter.requests.queued"])
+	assert.Equal(t, int64(1), got["ratelimiter.requests.accepted"])
+	assert.Equal(t, int64(1), got["ratelimiter.requests.rejected"])
+	assert.Equal(t, int64(1), got["ratelimiter.requests.timed_out"])
+	assertLimiterMetrics(t, reader, rl)
+
+	close(release)
+	<-done
+	<-done
+	assertLimiterMetrics(t, reader, rl)
+	assert.Equal(t, 2, rl.GetAcceptedRequests())
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -36,14 +35,8 @@ func NewErrFallbackUuidUsed(msg string) *ErrFallbackUuidUsed {
 
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		const (
-			traceName = "gin.middleware.RequestIDMiddleware"
-			spanName  = "get request ID"
-		)
-		tracer := otel.Tracer(traceName)
-		ctx, span := tracer.Start(c.Request.Context(), spanName)
-		c.Request = c.Request.WithContext(ctx)
-		defer span.End()
+		span, end := StartGinSpan(c, "gin.middleware.RequestIDMiddleware", "get request ID")
+		defer end()
 
 		requestID := c.GetHeader(RequestIDKey)
 		if requestID == "" {
@@ -54,7 +47,9 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		c.Set(RequestIDKey, requestID)
 		c.Writer.Header().Set(RequestIDKey, requestID)
 
-		span.SetAttributes(attribute.String("requestID", requestID))
+		if span.IsRecording() {
+			span.SetAttributes(attribute.String("requestID", requestID))
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+// Machine-readable error codes used in the error envelope.
+const (
+	ErrCodeInternal        = "internal_error"
+	ErrCodeTooManyRequests = "too_many_requests"
+	ErrCodeUnauthorized    = "unauthorized"
+	ErrCodeNotFound        = "not_found"
+	ErrCodeValidation      = "validation_failed"
+	ErrCodeTimeout         = "timeout"
+	ErrCodeCanceled        = "request_canceled"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the client
+// went away before the request was handled.
+const StatusClientClosedRequest = 499
+
+// ErrorBody is the payload of the uniform JSON error envelope.
+type ErrorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ErrorEnvelope is the uniform JSON shape of every error response:
+//
+//	{"error":{"code":"not_found","message":"...","details":[...],"request_id":"..."}}
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+// RespondError aborts the request and writes the uniform JSON error envelope.
+// The request ID is taken from the context via GetRequestIDFromCtx. If the stored
+// request ID can't be cast to string, the request_id field is omitted.
+func RespondError(c *gin.Context, status int, code string, msg string, details any) {
+	// a fallback uuid is still a valid request ID, so only the value matters here
+	requestID, _ := GetRequestIDFromCtx(c)
+	c.AbortWithStatusJSON(status, ErrorEnvelope{
+		Error: ErrorBody{
+			Code:      code,
+			Message:   msg,
+			Details:   details,
+			RequestID: requestID,
+		},
+	})
+}
+
+// StatusFromError maps typed errors to an HTTP status and machine-readable code.
+// Validation errors, including *val.ValidationError which unwraps to validator.ValidationErrors,
+// map to 422. Anything not recognized is treated as an internal error.
+func StatusFromError(err error) (int, string) {
+	var valErr validator.ValidationErrors
+	switch {
+	case errors.As(err, &valErr):
+		return http.StatusUnprocessableEntity, ErrCodeValidation
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, ErrCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest, ErrCodeCanceled
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal
+	}
+}
+
+// RespondWithError maps err through StatusFromError and writes the error envelope.
+// Internal errors get a generic message so implementation details don't leak to clients.
+func RespondWithError(c *gin.Context, err error, details any) {
+	status, code := StatusFromError(err)
+	msg := err.Error()
+	if status == http.StatusInternalServerError {
+		msg = http.StatusText(http.StatusInternalServerError)
+	}
+	RespondError(c, status, code, msg, details)
+}
+
+// Recovery returns a gin recovery middleware that answers panics with
+// a 500 error envelope instead of an empty body.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, _ any) {
+		RespondError(c, http.StatusInternalServerError, ErrCodeInternal,
+			http.StatusText(http.StatusInternalServerError), nil)
+	})
+}
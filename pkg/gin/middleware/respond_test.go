@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/KennyMacCormik/HerdMaster/pkg/val"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveErr(t *testing.T, withReqID bool, reqID string, h gin.HandlerFunc) (*httptest.ResponseRecorder, ErrorEnvelope) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if withReqID {
+		router.Use(RequestIDMiddleware())
+	}
+	router.GET("/", h)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	if reqID != "" {
+		req.Header.Set(RequestIDKey, reqID)
+	}
+	router.ServeHTTP(w, req)
+
+	var env ErrorEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env), "Response body should be a JSON error envelope")
+	return w, env
+}
+
+func TestRespondError_EnvelopeShape(t *testing.T) {
+	reqID := genUuid()
+	w, _ := serveErr(t, true, reqID, func(c *gin.Context) {
+		RespondError(c, http.StatusNotFound, ErrCodeNotFound, "dog not found", []string{"id"})
+	})
+
+	assert.Equal(t, http.StatusNotFound, w.Code, "Response status should be 404")
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var raw map[string]map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+	body, ok := raw["error"]
+	require.True(t, ok, "Envelope should contain the error object")
+	assert.Equal(t, ErrCodeNotFound, body["code"])
+	assert.Equal(t, "dog not found", body["message"])
+	assert.Equal(t, []any{"id"}, body["details"])
+	assert.Equal(t, reqID, body["request_id"])
+}
+
+func TestRespondError_OmitsEmptyDetails(t *testing.T) {
+	w, env := serveErr(t, true, "", func(c *gin.Context) {
+		RespondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "missing credentials", nil)
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotContains(t, w.Body.String(), "details", "Nil details should be omitted")
+	assert.Equal(t, w.Header().Get(RequestIDKey), env.Error.RequestID, "Envelope request ID should match header")
+}
+
+func TestRespondError_FallbackRequestID(t *testing.T) {
+	w, env := serveErr(t, false, "", func(c *gin.Context) {
+		RespondError(c, http.StatusInternalServerError, ErrCodeInternal, "boom", nil)
+	})
+
+	assert.NotEmpty(t, env.Error.RequestID, "Fallback request ID should be present")
+	assert.Equal(t, w.Header().Get(RequestIDKey), env.Error.RequestID)
+}
+
+func TestRespondError_TypeCastFailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(RequestIDKey, 12345)
+		c.Next()
+	})
+	router.GET("/", func(c *gin.Context) {
+		RespondError(c, http.StatusInternalServerError, ErrCodeInternal, "boom", nil)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "request_id", "Unusable request ID should be omitted")
+}
+
+func TestRespondWithError_ErrorClasses(t *testing.T) {
+	valErr := fmt.Errorf("wrapped: %w", validator.ValidationErrors{})
+	structErr := val.GetValidator().ValidateStruct(struct {
+		Port int `validate:"port"`
+	}{})
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+	}{
+		{"validation", valErr, http.StatusUnprocessableEntity, ErrCodeValidation, valErr.Error()},
+		{"val validation", structErr, http.StatusUnprocessableEntity, ErrCodeValidation, structErr.Error()},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, ErrCodeTimeout, context.DeadlineExceeded.Error()},
+		{"canceled", context.Canceled, StatusClientClosedRequest, ErrCodeCanceled, context.Canceled.Error()},
+		{"unknown", errors.New("db password is hunter2"), http.StatusInternalServerError, ErrCodeInternal,
+			http.StatusText(http.StatusInternalServerError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqID := genUuid()
+			w, env := serveErr(t, true, reqID, func(c *gin.Context) {
+				RespondWithError(c, tt.err, nil)
+			})
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantCode, env.Error.Code)
+			assert.Equal(t, tt.wantMsg, env.Error.Message)
+			assert.Equal(t, reqID, env.Error.RequestID)
+		})
+	}
+}
+
+func TestRecovery_Envelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recovery(), RequestIDMiddleware())
+	router.GET("/", func(c *gin.Context) {
+		panic("test panic")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+
+	var env ErrorEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ErrCodeInternal, env.Error.Code)
+	assert.Equal(t, w.Header().Get(RequestIDKey), env.Error.RequestID)
+}
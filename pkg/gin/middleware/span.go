@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	hmotel "github.com/KennyMacCormik/HerdMaster/pkg/otel"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var (
+	// disabledSpan is returned by StartGinSpan while tracing is disabled.
+	disabledSpan trace.Span = noop.Span{}
+	disabledEnd             = func() {}
+)
+
+// StartGinSpan starts a span named name from the tracer named tracerName as a child of the
+// request context, replaces the request with one carrying the new span, and returns the span
+// together with the function that ends it. Each middleware uses its own tracer name, e.g.
+// "gin.middleware.RequestIDMiddleware", which becomes the instrumentation scope of its spans.
+// While tracing is disabled (see pkg/otel.Enabled), it leaves the request untouched
+// and returns a non-recording span, so callers should guard attribute building
+// with span.IsRecording().
+//
+// Example usage:
+//
+//	span, end := StartGinSpan(c, "gin.middleware.MyMiddleware", "my middleware", attribute.String("key", "value"))
+//	defer end()
+func StartGinSpan(c *gin.Context, tracerName, name string, attrs ...attribute.KeyValue) (trace.Span, func()) {
+	if !hmotel.Enabled() {
+		return disabledSpan, disabledEnd
+	}
+	ctx, span := otel.Tracer(tracerName).Start(c.Request.Context(), name, trace.WithAttributes(attrs...))
+	c.Request = c.Request.WithContext(ctx)
+	return span, func() { span.End() }
+}
+
+// AddEventIfErr adds an event named msg with the error attribute set to err.Error().
+// It does nothing if err is nil or span is not recording, so callers don't have to guard against either.
+func AddEventIfErr(span trace.Span, msg string, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+	span.AddEvent(msg, trace.WithAttributes(attribute.String("error", err.Error())))
+}
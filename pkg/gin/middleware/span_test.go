@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	hmotel "github.com/KennyMacCormik/HerdMaster/pkg/otel"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newSpanRecorder installs a recording TracerProvider for the duration of the test.
+func newSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	prev, prevEnabled := otel.GetTracerProvider(), hmotel.Enabled()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	hmotel.SetEnabled(true)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		hmotel.SetEnabled(prevEnabled)
+	})
+	return sr
+}
+
+func eventNames(span sdktrace.ReadOnlySpan) []string {
+	names := make([]string, 0, len(span.Events()))
+	for _, e := range span.Events() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func spanByName(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name() == name {
+			return s
+		}
+	}
+	require.Failf(t, "span not found", "no span named %q", name)
+	return nil
+}
+
+func TestStartGinSpan(t *testing.T) {
+	sr := newSpanRecorder(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		span, end := StartGinSpan(c, "test tracer", "test span", attribute.String("key", "value"))
+		assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(c.Request.Context()),
+			"request context should carry the new span")
+		end()
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "test span", spans[0].Name())
+	assert.Equal(t, "test tracer", spans[0].InstrumentationScope().Name)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("key", "value"))
+}
+
+func TestAddEventIfErr(t *testing.T) {
+	sr := newSpanRecorder(t)
+	_, span := otel.Tracer("test").Start(context.Background(), "span")
+	AddEventIfErr(span, "nil error", nil)
+	AddEventIfErr(span, "real error", errors.New("boom"))
+	span.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, []string{"real error"}, eventNames(spans[0]))
+	assert.Equal(t, []attribute.KeyValue{attribute.String("error", "boom")}, spans[0].Events()[0].Attributes)
+}
+
+func TestRequestIDMiddleware_SpanStructure(t *testing.T) {
+	sr := newSpanRecorder(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "get request ID", spans[0].Name())
+	assert.Equal(t, "gin.middleware.RequestIDMiddleware", spans[0].InstrumentationScope().Name)
+	assert.Equal(t, []string{"no request id in header"}, eventNames(spans[0]))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("requestID", w.Header().Get(RequestIDKey)))
+}
+
+func TestRateLimiter_SpanStructure(t *testing.T) {
+	sr := newSpanRecorder(t)
+	gin.SetMode(gin.TestMode)
+	rl := NewRateLimiter(1, 10, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), rl.GetRateLimiter())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	reqIDSpan := spanByName(t, spans, "get request ID")
+	limiterSpan := spanByName(t, spans, "rate limiting middleware")
+	assert.Equal(t, "gin.middleware.RequestIDMiddleware", reqIDSpan.InstrumentationScope().Name)
+	assert.Equal(t, "gin.middleware.GetRateLimiter", limiterSpan.InstrumentationScope().Name)
+	assert.Equal(t, reqIDSpan.SpanContext().SpanID(), limiterSpan.Parent().SpanID(),
+		"limiter span should be a child of the request ID span")
+	assert.Equal(t, []string{"queuing request", "request accepted", "request completed"}, eventNames(limiterSpan))
+	for _, key := range []attribute.Key{"running", "total", "timedOut", "rejected"} {
+		found := false
+		for _, a := range limiterSpan.Attributes() {
+			found = found || a.Key == key
+		}
+		assert.True(t, found, "limiter span should carry the %s attribute", key)
+	}
+}
+
+func TestRateLimiter_RejectSpanWithoutError(t *testing.T) {
+	sr := newSpanRecorder(t)
+	gin.SetMode(gin.TestMode)
+	// maxWait of 1 rejects every request, while the request ID is present so err is nil
+	rl := NewRateLimiter(1, 1, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), rl.GetRateLimiter())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	assert.NotPanics(t, func() { router.ServeHTTP(w, req) })
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	limiterSpan := spanByName(t, sr.Ended(), "rate limiting middleware")
+	assert.Equal(t, []string{"too many total requests, rejecting request"}, eventNames(limiterSpan))
+}
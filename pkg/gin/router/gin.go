@@ -2,7 +2,10 @@
 // It simplifies the creation of a Gin router with preconfigured middleware and route handlers.
 package router
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/KennyMacCormik/HerdMaster/pkg/gin/middleware"
+	"github.com/gin-gonic/gin"
+)
 
 // GinFactory is a factory for managing middleware and handlers in a Gin application.
 // It provides methods for adding middleware, adding handlers, and creating a router instance.
@@ -12,9 +15,9 @@ type GinFactory struct {
 }
 
 // NewGinFactory initializes a new instance of GinFactory.
-// It includes the default gin.Recovery middleware to handle panics gracefully.
+// It includes the middleware.Recovery middleware to answer panics with a JSON error envelope.
 func NewGinFactory() *GinFactory {
-	return &GinFactory{middleware: []gin.HandlerFunc{gin.Recovery()}, handlers: make([]func(router *gin.Engine), 0)}
+	return &GinFactory{middleware: []gin.HandlerFunc{middleware.Recovery()}, handlers: make([]func(router *gin.Engine), 0)}
 }
 
 // AddMiddleware adds middleware to the GinFactory.
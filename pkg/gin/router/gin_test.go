@@ -182,5 +182,5 @@ func TestDefaultRecoveryMiddleware(t *testing.T) {
 
 	// Assertions
 	assert.Equal(t, http.StatusInternalServerError, w.Code, "Recovery middleware should handle panics and return 500")
-	assert.Contains(t, w.Body.String(), "", "Response body be empty as default recovery middleware does not include a body in its response when a panic occurs")
+	assert.Contains(t, w.Body.String(), `"code":"internal_error"`, "Response body should contain the JSON error envelope")
 }
@@ -0,0 +1,28 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ctxKey is the context key under which IntoContext stores a logger.
+type ctxKey struct{}
+
+// IntoContext returns a copy of ctx carrying lg. Use FromContext to retrieve it.
+//
+// Example usage:
+//
+//	ctx = log.IntoContext(ctx, lg.With("UUID", uuid))
+func IntoContext(ctx context.Context, lg *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lg)
+}
+
+// FromContext returns the logger stored in ctx by IntoContext.
+// If ctx carries no logger, it returns the singleton logger from GetLogger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if lg, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok && lg != nil {
+		return lg
+	}
+	lg, _ := GetLogger()
+	return lg
+}
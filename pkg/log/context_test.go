@@ -0,0 +1,23 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestIntoContext_FromContext(t *testing.T) {
+	output := &bytes.Buffer{}
+	lg := slog.New(slog.NewTextHandler(output, nil)).With("UUID", "123")
+
+	FromContext(IntoContext(context.Background(), lg)).Info("from context")
+	assert.Contains(t, output.String(), "UUID=123", "expected the stored logger to be returned")
+}
+
+func TestFromContext_FallsBackToSingleton(t *testing.T) {
+	defer resetLoggerConf()
+	singleton, _ := GetLogger()
+	assert.Same(t, singleton, FromContext(context.Background()), "expected the singleton logger without a stored one")
+}
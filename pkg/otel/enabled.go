@@ -0,0 +1,24 @@
+package otel
+
+import "sync/atomic"
+
+// enabled reports whether a TracerProvider is active. The gin middlewares consult it
+// to skip span creation entirely when tracing is off.
+var enabled atomic.Bool
+
+// Enabled reports whether tracing is enabled. It is set by a successful Init
+// and cleared by the returned shutdown function.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// SetEnabled overrides the tracing flag. Use it when the application installs its own
+// global TracerProvider instead of calling Init, so the middlewares keep emitting spans.
+//
+// Example usage:
+//
+//	otel.SetTracerProvider(tp) // go.opentelemetry.io/otel
+//	hmotel.SetEnabled(true)
+func SetEnabled(v bool) {
+	enabled.Store(v)
+}
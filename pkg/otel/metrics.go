@@ -0,0 +1,185 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	promexporter "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"sync"
+	"time"
+)
+
+// ExporterPrometheus exposes metrics through a Prometheus registerer instead of pushing them.
+const ExporterPrometheus = "prometheus"
+
+const (
+	processScopeName       = "github.com/KennyMacCormik/HerdMaster/pkg/otel"
+	defaultMetricsInterval = time.Minute
+)
+
+var (
+	// activeMetricsShutdown is non-nil while a provider installed by InitMetrics is registered.
+	activeMetricsShutdown func(context.Context) error
+	processStart          = time.Now()
+)
+
+// InitMetrics builds a MeterProvider according to c, registers it globally, and starts
+// Go runtime and process instrumentation. It shares the resource attributes used by Init.
+//
+// The reader is selected by c.MetricsExporter: ExporterOtlpGrpc and ExporterOtlpHttp push
+// to c.Endpoint every c.MetricsInterval, ExporterPrometheus registers with c.PromRegisterer.
+// A non-nil c.MetricReader takes precedence over the exporter selection.
+//
+// Like Init, InitMetrics returns a no-op shutdown when disabled and is idempotent.
+func InitMetrics(ctx context.Context, c Config) (shutdown func(context.Context) error, err error) {
+	if !c.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	initMtx.Lock()
+	defer initMtx.Unlock()
+
+	if activeMetricsShutdown != nil {
+		return activeMetricsShutdown, nil
+	}
+
+	c = normalizeConfig(c)
+	if err = validateMetricsConfig(c); err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	reader, err := newMetricReader(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s metric reader: %w", c.MetricsExporter, err)
+	}
+
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res))
+
+	if err = runtime.Start(runtime.WithMeterProvider(mp)); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to start runtime instrumentation: %w", err), mp.Shutdown(ctx))
+	}
+	if err = startProcessMetrics(mp.Meter(processScopeName)); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to start process instrumentation: %w", err), mp.Shutdown(ctx))
+	}
+
+	otel.SetMeterProvider(mp)
+
+	activeMetricsShutdown = newMetricsShutdown(mp, c.ShutdownTimeout)
+	return activeMetricsShutdown, nil
+}
+
+// Meter returns a named meter from the globally registered MeterProvider.
+// It lets middleware and storage layers create instruments without importing the SDK.
+// Before InitMetrics is called, the returned meter is backed by the global delegate
+// and starts recording once a provider is registered.
+func Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return otel.Meter(name, opts...)
+}
+
+// newMetricsShutdown returns a function that flushes and stops mp within timeout,
+// and restores the no-op global provider so InitMetrics can be called again.
+func newMetricsShutdown(mp *sdkmetric.MeterProvider, timeout time.Duration) func(context.Context) error {
+	var once sync.Once
+	var shutdownErr error
+	return func(ctx context.Context) error {
+		once.Do(func() {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			shutdownErr = errors.Join(mp.ForceFlush(ctx), mp.Shutdown(ctx))
+
+			initMtx.Lock()
+			defer initMtx.Unlock()
+			otel.SetMeterProvider(metricnoop.NewMeterProvider())
+			activeMetricsShutdown = nil
+		})
+		return shutdownErr
+	}
+}
+
+// validateMetricsConfig checks the metrics exporter selection and the endpoint it requires.
+func validateMetricsConfig(c Config) error {
+	if c.MetricReader != nil {
+		return nil
+	}
+	switch c.MetricsExporter {
+	case ExporterOtlpGrpc, ExporterOtlpHttp:
+		if c.Endpoint == "" {
+			return fmt.Errorf("endpoint is required for metrics exporter %s", c.MetricsExporter)
+		}
+	case ExporterPrometheus:
+	default:
+		return fmt.Errorf("unsupported metrics exporter %q", c.MetricsExporter)
+	}
+	if c.MetricsInterval < 0 {
+		return fmt.Errorf("metrics interval must not be negative")
+	}
+	return nil
+}
+
+// newMetricReader creates the metric reader selected by c.MetricsExporter.
+func newMetricReader(ctx context.Context, c Config) (sdkmetric.Reader, error) {
+	if c.MetricReader != nil {
+		return c.MetricReader, nil
+	}
+
+	interval := c.MetricsInterval
+	if interval == 0 {
+		interval = defaultMetricsInterval
+	}
+
+	switch c.MetricsExporter {
+	case ExporterOtlpGrpc:
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpointURL(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		exp, err := otlpmetricgrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(interval)), nil
+	case ExporterOtlpHttp:
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpointURL(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		exp, err := otlpmetrichttp.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(interval)), nil
+	default:
+		reg := c.PromRegisterer
+		if reg == nil {
+			reg = prometheus.DefaultRegisterer
+		}
+		return promexporter.New(promexporter.WithRegisterer(reg))
+	}
+}
+
+// startProcessMetrics registers process level instruments not covered by the runtime instrumentation.
+func startProcessMetrics(meter metric.Meter) error {
+	_, err := meter.Float64ObservableGauge(
+		"process.uptime",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time elapsed since the process started."),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			o.Observe(time.Since(processStart).Seconds())
+			return nil
+		}),
+	)
+	return err
+}
@@ -0,0 +1,129 @@
+package otel
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"testing"
+)
+
+func collectMetricNames(t *testing.T, reader *sdkmetric.ManualReader) map[string]struct{} {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	names := make(map[string]struct{})
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names[m.Name] = struct{}{}
+		}
+	}
+	return names
+}
+
+func TestInitMetrics_Disabled(t *testing.T) {
+	shutdown, err := InitMetrics(context.Background(), Config{Enabled: false, MetricsExporter: "bogus"})
+	require.NoError(t, err)
+	require.NotNil(t, shutdown)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestInitMetrics_RuntimeInstruments(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	shutdown, err := InitMetrics(context.Background(), Config{
+		Enabled:        true,
+		ServiceVersion: "1.2.3",
+		MetricReader:   reader,
+	})
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+	names := collectMetricNames(t, reader)
+	for _, name := range []string{
+		"process.runtime.go.goroutines",
+		"process.runtime.go.mem.heap_alloc",
+		"process.runtime.go.gc.count",
+		"process.uptime",
+	} {
+		assert.Contains(t, names, name, "runtime instrument %s should be collected", name)
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	assert.Contains(t, rm.Resource.String(), "herdmaster", "metrics should share the tracing resource")
+	assert.Contains(t, rm.Resource.String(), "1.2.3", "metrics should share the tracing resource")
+}
+
+func TestInitMetrics_MeterHelper(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	shutdown, err := InitMetrics(context.Background(), Config{Enabled: true, MetricReader: reader})
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+	counter, err := Meter("test").Int64Counter("test.counter")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 3)
+
+	assert.Contains(t, collectMetricNames(t, reader), "test.counter",
+		"instruments created through Meter should be collected")
+}
+
+func TestInitMetrics_Idempotent(t *testing.T) {
+	shutdown1, err := InitMetrics(context.Background(), Config{Enabled: true, MetricReader: sdkmetric.NewManualReader()})
+	require.NoError(t, err)
+	mp := otel.GetMeterProvider()
+
+	shutdown2, err := InitMetrics(context.Background(), Config{Enabled: true, MetricReader: sdkmetric.NewManualReader()})
+	require.NoError(t, err)
+	assert.Equal(t, mp, otel.GetMeterProvider(), "second InitMetrics should keep the active provider")
+
+	require.NoError(t, shutdown2(context.Background()))
+	require.NoError(t, shutdown1(context.Background()))
+	_, ok := otel.GetMeterProvider().(*sdkmetric.MeterProvider)
+	assert.False(t, ok, "shutdown should unregister the SDK provider")
+}
+
+func TestInitMetrics_Prometheus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	shutdown, err := InitMetrics(context.Background(), Config{
+		Enabled:         true,
+		MetricsExporter: ExporterPrometheus,
+		PromRegisterer:  reg,
+	})
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	names := make([]string, 0, len(families))
+	for _, f := range families {
+		names = append(names, f.GetName())
+	}
+	assert.Contains(t, names, "process_runtime_go_goroutines", "runtime metrics should be exposed to Prometheus")
+}
+
+func TestInitMetrics_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Config
+		msg  string
+	}{
+		{"unsupported exporter", Config{Enabled: true, MetricsExporter: "statsd"}, "unsupported metrics exporter"},
+		{"missing endpoint", Config{Enabled: true, MetricsExporter: ExporterOtlpGrpc}, "endpoint is required"},
+		{"negative interval", Config{Enabled: true, MetricsExporter: ExporterPrometheus, MetricsInterval: -1},
+			"must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := InitMetrics(context.Background(), tt.conf)
+			require.Error(t, err)
+			assert.Nil(t, shutdown)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Package otel provides a reusable OpenTelemetry initializer for HerdMaster microservices.
+// It builds a TracerProvider with an OTLP (gRPC or HTTP) or stdout exporter, attaches
+// service resource attributes, and registers the provider globally, so the tracing
+// already present in the gin middlewares starts exporting spans. Until Init succeeds,
+// Enabled reports false and the middlewares skip span creation altogether.
+// InitMetrics does the same for a MeterProvider and adds Go runtime instrumentation.
+//
+// Example usage:
+//
+//	shutdown, err := otel.Init(ctx, otel.Config{
+//		Enabled:     true,
+//		Exporter:    otel.ExporterOtlpGrpc,
+//		Endpoint:    "http://127.0.0.1:4317",
+//		Insecure:    true,
+//		ServiceName: "herdmaster",
+//	})
+//	if err != nil {
+//		// Handle error
+//	}
+//	defer shutdown(context.Background())
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace/noop"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Supported span exporters.
+const (
+	ExporterOtlpGrpc = "otlp_grpc"
+	ExporterOtlpHttp = "otlp_http"
+	ExporterStdout   = "stdout"
+)
+
+const (
+	defaultServiceName     = "herdmaster"
+	defaultShutdownTimeout = 5 * time.Second
+)
+
+var (
+	initMtx sync.Mutex
+	// activeShutdown is non-nil while a provider installed by Init is registered.
+	activeShutdown func(context.Context) error
+)
+
+// Config represents the configuration for the OTel tracing system.
+// It mirrors genCfg.OtelConfig and adds the exporter selection, resource attributes,
+// and batch processor tuning knobs.
+//
+// Fields:
+//   - Enabled: Turns tracing on. When false, Init returns a no-op shutdown.
+//   - Exporter: One of ExporterOtlpGrpc, ExporterOtlpHttp, or ExporterStdout.
+//   - Endpoint: URL of the OTLP collector. Required for the OTLP exporters.
+//   - Insecure: Disables TLS for the OTLP exporters.
+//   - ShutdownTimeout: Upper bound for flushing spans on shutdown. Defaults to 5s.
+//   - ServiceName, ServiceVersion, InstanceID: Resource attributes. ServiceName defaults to "herdmaster".
+//   - BatchTimeout, MaxExportBatchSize, MaxQueueSize: Batch span processor tuning. Zero keeps SDK defaults.
+//   - Output: Destination of the stdout exporter. Defaults to os.Stdout.
+//   - MetricsExporter: One of ExporterOtlpGrpc, ExporterOtlpHttp, or ExporterPrometheus. Used by InitMetrics.
+//   - MetricsInterval: Push interval of the OTLP metric exporters. Defaults to 1m.
+//   - MetricsBuckets: Request duration histogram boundaries in seconds, for middleware.WithDurationBuckets.
+//   - MetricReader: Custom metric reader overriding MetricsExporter, e.g. a manual reader in tests.
+//   - PromRegisterer: Registerer used by the Prometheus exporter. Defaults to prometheus.DefaultRegisterer.
+//   - Sampler: One of the Sampler* constants. Defaults to SamplerParentBasedAlwaysOn.
+//   - SamplerRatio: Ratio in [0, 1] for SamplerParentBasedTraceIDRatio.
+//   - SamplerRateLimit: Maximum root spans per second for SamplerParentBasedRateLimited.
+//   - NeverSampleRoutes: Routes (http.route or url.path start attributes) that are never sampled, e.g. "/healthz".
+//   - ShouldSample: Optional hook; returning false drops the span regardless of the sampler.
+//   - Propagators: Ordered list of PropagatorTraceContext, PropagatorBaggage, or PropagatorB3.
+//     Defaults to tracecontext and baggage.
+type Config struct {
+	Enabled            bool          `mapstructure:"otel_enabled"`
+	Exporter           string        `mapstructure:"otel_exporter"`
+	Endpoint           string        `mapstructure:"otel_endpoint"`
+	Insecure           bool          `mapstructure:"otel_insecure"`
+	ShutdownTimeout    time.Duration `mapstructure:"otel_shutdown_timeout"`
+	ServiceName        string        `mapstructure:"otel_service_name"`
+	ServiceVersion     string        `mapstructure:"otel_service_version"`
+	InstanceID         string        `mapstructure:"otel_instance_id"`
+	BatchTimeout       time.Duration `mapstructure:"otel_batch_timeout"`
+	MaxExportBatchSize int           `mapstructure:"otel_max_export_batch_size"`
+	MaxQueueSize       int           `mapstructure:"otel_max_queue_size"`
+	Output             io.Writer     `mapstructure:"-"`
+
+	MetricsExporter string                `mapstructure:"otel_metrics_exporter"`
+	MetricsInterval time.Duration         `mapstructure:"otel_metrics_interval"`
+	MetricsBuckets  []float64             `mapstructure:"otel_metrics_buckets"`
+	MetricReader    sdkmetric.Reader      `mapstructure:"-"`
+	PromRegisterer  prometheus.Registerer `mapstructure:"-"`
+
+	Sampler           string                                   `mapstructure:"otel_sampler"`
+	SamplerRatio      float64                                  `mapstructure:"otel_sampler_ratio"`
+	SamplerRateLimit  int                                      `mapstructure:"otel_sampler_rate_limit"`
+	NeverSampleRoutes []string                                 `mapstructure:"otel_never_sample_routes"`
+	ShouldSample      func(p sdktrace.SamplingParameters) bool `mapstructure:"-"`
+
+	Propagators []string `mapstructure:"otel_propagators"`
+}
+
+// Init builds a TracerProvider according to c and registers it globally
+// together with the composite propagator selected by c.Propagators.
+// The returned shutdown function flushes pending spans and unregisters the provider.
+//
+// Init is safe to call when tracing is disabled: it returns a no-op shutdown and a nil error.
+// Init is idempotent: while a provider installed by a previous call is active, further calls
+// return its shutdown function without creating a new provider.
+func Init(ctx context.Context, c Config) (shutdown func(context.Context) error, err error) {
+	if !c.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	initMtx.Lock()
+	defer initMtx.Unlock()
+
+	if activeShutdown != nil {
+		return activeShutdown, nil
+	}
+
+	c = normalizeConfig(c)
+	if err = validateConfig(c); err != nil {
+		return nil, err
+	}
+
+	sampler, err := newSampler(c)
+	if err != nil {
+		return nil, err
+	}
+
+	propagator, err := newPropagator(c)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	exp, err := newExporter(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", c.Exporter, err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp, batchOptions(c)...),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	enabled.Store(true)
+
+	activeShutdown = newShutdown(tp, c.ShutdownTimeout)
+	return activeShutdown, nil
+}
+
+// newShutdown returns a function that flushes and stops tp within timeout,
+// and restores the no-op global provider so Init can be called again.
+func newShutdown(tp *sdktrace.TracerProvider, timeout time.Duration) func(context.Context) error {
+	var once sync.Once
+	var shutdownErr error
+	return func(ctx context.Context) error {
+		once.Do(func() {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			shutdownErr = errors.Join(tp.ForceFlush(ctx), tp.Shutdown(ctx))
+
+			initMtx.Lock()
+			defer initMtx.Unlock()
+			otel.SetTracerProvider(noop.NewTracerProvider())
+			enabled.Store(false)
+			activeShutdown = nil
+		})
+		return shutdownErr
+	}
+}
+
+// normalizeConfig fills in defaults for unset optional fields.
+func normalizeConfig(c Config) Config {
+	if c.ServiceName == "" {
+		c.ServiceName = defaultServiceName
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = defaultShutdownTimeout
+	}
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+	return c
+}
+
+// validateConfig checks the exporter selection and the endpoint it requires.
+func validateConfig(c Config) error {
+	switch c.Exporter {
+	case ExporterOtlpGrpc, ExporterOtlpHttp:
+		if c.Endpoint == "" {
+			return fmt.Errorf("endpoint is required for exporter %s", c.Exporter)
+		}
+	case ExporterStdout:
+	default:
+		return fmt.Errorf("unsupported exporter %q", c.Exporter)
+	}
+	if c.MaxExportBatchSize < 0 || c.MaxQueueSize < 0 || c.BatchTimeout < 0 {
+		return fmt.Errorf("batch processor settings must not be negative")
+	}
+	return nil
+}
+
+// newResource describes the service emitting the spans.
+func newResource(c Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(c.ServiceName)}
+	if c.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(c.ServiceVersion))
+	}
+	if c.InstanceID != "" {
+		attrs = append(attrs, semconv.ServiceInstanceID(c.InstanceID))
+	}
+	return resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+}
+
+// newExporter creates the span exporter selected by c.Exporter.
+func newExporter(ctx context.Context, c Config) (sdktrace.SpanExporter, error) {
+	switch c.Exporter {
+	case ExporterOtlpGrpc:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpointURL(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterOtlpHttp:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return stdouttrace.New(stdouttrace.WithWriter(c.Output))
+	}
+}
+
+// batchOptions converts the tuning knobs into batch span processor options.
+// Zero values keep the SDK defaults.
+func batchOptions(c Config) []sdktrace.BatchSpanProcessorOption {
+	var opts []sdktrace.BatchSpanProcessorOption
+	if c.BatchTimeout > 0 {
+		opts = append(opts, sdktrace.WithBatchTimeout(c.BatchTimeout))
+	}
+	if c.MaxExportBatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(c.MaxExportBatchSize))
+	}
+	if c.MaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(c.MaxQueueSize))
+	}
+	return opts
+}
@@ -0,0 +1,113 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"testing"
+	"time"
+)
+
+func stdoutConfig(buf *bytes.Buffer) Config {
+	return Config{
+		Enabled:        true,
+		Exporter:       ExporterStdout,
+		ServiceVersion: "1.2.3",
+		InstanceID:     "instance-1",
+		BatchTimeout:   time.Hour, // only shutdown may flush
+		Output:         buf,
+	}
+}
+
+func TestInit_Disabled(t *testing.T) {
+	before := otel.GetTracerProvider()
+
+	shutdown, err := Init(context.Background(), Config{Enabled: false, Exporter: "bogus"})
+	require.NoError(t, err, "disabled Init should not validate or fail")
+	require.NotNil(t, shutdown, "disabled Init should return a no-op shutdown")
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, before, otel.GetTracerProvider(), "disabled Init should not touch the global provider")
+	assert.False(t, Enabled(), "disabled Init should not enable tracing")
+}
+
+func TestInit_StdoutExportsOnShutdown(t *testing.T) {
+	buf := &bytes.Buffer{}
+	shutdown, err := Init(context.Background(), stdoutConfig(buf))
+	require.NoError(t, err)
+
+	_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	assert.True(t, ok, "Init should register the SDK provider globally")
+	assert.True(t, Enabled(), "Init should enable tracing")
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+	assert.Empty(t, buf.String(), "span should still be batched before shutdown")
+
+	require.NoError(t, shutdown(context.Background()))
+	out := buf.String()
+	assert.Contains(t, out, `"Name":"test-span"`, "shutdown should flush pending spans")
+	assert.Contains(t, out, "herdmaster", "default service name should be exported")
+	assert.Contains(t, out, "1.2.3", "service version should be exported")
+	assert.Contains(t, out, "instance-1", "instance id should be exported")
+
+	_, ok = otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	assert.False(t, ok, "shutdown should unregister the SDK provider")
+	assert.False(t, Enabled(), "shutdown should disable tracing")
+	assert.NoError(t, shutdown(context.Background()), "repeated shutdown should be harmless")
+}
+
+func TestInit_Idempotent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	shutdown1, err := Init(context.Background(), stdoutConfig(buf))
+	require.NoError(t, err)
+	tp := otel.GetTracerProvider()
+
+	shutdown2, err := Init(context.Background(), stdoutConfig(&bytes.Buffer{}))
+	require.NoError(t, err)
+	assert.Equal(t, tp, otel.GetTracerProvider(), "second Init should keep the active provider")
+
+	require.NoError(t, shutdown2(context.Background()))
+	require.NoError(t, shutdown1(context.Background()))
+
+	// after shutdown a fresh provider can be installed
+	shutdown3, err := Init(context.Background(), stdoutConfig(buf))
+	require.NoError(t, err)
+	assert.NotEqual(t, tp, otel.GetTracerProvider(), "Init after shutdown should install a new provider")
+	require.NoError(t, shutdown3(context.Background()))
+}
+
+func TestInit_OtlpHttp(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{
+		Enabled:  true,
+		Exporter: ExporterOtlpHttp,
+		Endpoint: "http://127.0.0.1:4318",
+		Insecure: true,
+	})
+	require.NoError(t, err, "OTLP exporter should be created without connecting")
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestInit_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Config
+		msg  string
+	}{
+		{"unsupported exporter", Config{Enabled: true, Exporter: "zipkin"}, "unsupported exporter"},
+		{"missing grpc endpoint", Config{Enabled: true, Exporter: ExporterOtlpGrpc}, "endpoint is required"},
+		{"missing http endpoint", Config{Enabled: true, Exporter: ExporterOtlpHttp}, "endpoint is required"},
+		{"negative batch", Config{Enabled: true, Exporter: ExporterStdout, MaxQueueSize: -1}, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Init(context.Background(), tt.conf)
+			require.Error(t, err)
+			assert.Nil(t, shutdown)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
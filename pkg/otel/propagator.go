@@ -0,0 +1,47 @@
+package otel
+
+import (
+	"fmt"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Supported propagators.
+const (
+	PropagatorTraceContext = "tracecontext"
+	PropagatorBaggage      = "baggage"
+	PropagatorB3           = "b3"
+)
+
+// defaultPropagators are installed when Config.Propagators is empty.
+var defaultPropagators = []string{PropagatorTraceContext, PropagatorBaggage}
+
+// newPropagator builds a composite propagator from the names in c.Propagators,
+// preserving their order. Duplicates are ignored.
+func newPropagator(c Config) (propagation.TextMapPropagator, error) {
+	names := c.Propagators
+	if len(names) == 0 {
+		names = defaultPropagators
+	}
+
+	seen := make(map[string]struct{}, len(names))
+	props := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		switch name {
+		case PropagatorTraceContext:
+			props = append(props, propagation.TraceContext{})
+		case PropagatorBaggage:
+			props = append(props, propagation.Baggage{})
+		case PropagatorB3:
+			props = append(props, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader|b3.B3SingleHeader)))
+		default:
+			return nil, fmt.Errorf("unsupported propagator %q", name)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(props...), nil
+}
@@ -0,0 +1,73 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"testing"
+)
+
+func TestNewPropagator_Fields(t *testing.T) {
+	tests := []struct {
+		name   string
+		conf   Config
+		want   []string
+		absent []string
+	}{
+		{"default", Config{}, []string{"traceparent", "tracestate", "baggage"}, []string{"b3"}},
+		{"tracecontext only", Config{Propagators: []string{PropagatorTraceContext}},
+			[]string{"traceparent", "tracestate"}, []string{"baggage", "b3"}},
+		{"with b3", Config{Propagators: []string{PropagatorTraceContext, PropagatorBaggage, PropagatorB3}},
+			[]string{"traceparent", "baggage", "b3", "x-b3-traceid"}, nil},
+		{"duplicates ignored", Config{Propagators: []string{PropagatorBaggage, PropagatorBaggage}},
+			[]string{"baggage"}, []string{"traceparent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newPropagator(tt.conf)
+			require.NoError(t, err)
+			fields := p.Fields()
+			for _, f := range tt.want {
+				assert.Contains(t, fields, f)
+			}
+			for _, f := range tt.absent {
+				assert.NotContains(t, fields, f)
+			}
+		})
+	}
+}
+
+func TestNewPropagator_Invalid(t *testing.T) {
+	_, err := newPropagator(Config{Propagators: []string{PropagatorTraceContext, "jaeger"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported propagator "jaeger"`)
+}
+
+func TestInit_InstallsPropagator(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{
+		Enabled:     true,
+		Exporter:    ExporterStdout,
+		Output:      &bytes.Buffer{},
+		Propagators: []string{PropagatorBaggage, PropagatorB3},
+	})
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+	fields := otel.GetTextMapPropagator().Fields()
+	assert.Contains(t, fields, "baggage")
+	assert.Contains(t, fields, "b3")
+	assert.NotContains(t, fields, "traceparent")
+}
+
+func TestInit_InvalidPropagator(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{
+		Enabled:     true,
+		Exporter:    ExporterStdout,
+		Propagators: []string{"jaeger"},
+	})
+	require.Error(t, err)
+	assert.Nil(t, shutdown)
+}
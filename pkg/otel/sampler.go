@@ -0,0 +1,132 @@
+package otel
+
+import (
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"sync"
+	"time"
+)
+
+// Supported samplers.
+const (
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	SamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+	SamplerAlwaysOff               = "always_off"
+	SamplerParentBasedRateLimited  = "parentbased_ratelimited"
+)
+
+// routeAttributes are the span start attributes inspected for Config.NeverSampleRoutes.
+var routeAttributes = []attribute.Key{semconv.HTTPRouteKey, semconv.URLPathKey}
+
+// newSampler creates the sampler selected by c.Sampler, wrapped with the per-route overrides.
+// An empty c.Sampler selects SamplerParentBasedAlwaysOn.
+func newSampler(c Config) (sdktrace.Sampler, error) {
+	var base sdktrace.Sampler
+	switch c.Sampler {
+	case SamplerParentBasedAlwaysOn, "":
+		base = sdktrace.ParentBased(sdktrace.AlwaysSample())
+	case SamplerParentBasedTraceIDRatio:
+		if c.SamplerRatio < 0 || c.SamplerRatio > 1 {
+			return nil, fmt.Errorf("sampler ratio must be within [0, 1], got %v", c.SamplerRatio)
+		}
+		base = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SamplerRatio))
+	case SamplerAlwaysOff:
+		base = sdktrace.NeverSample()
+	case SamplerParentBasedRateLimited:
+		if c.SamplerRateLimit < 1 {
+			return nil, fmt.Errorf("sampler rate limit must be positive, got %d", c.SamplerRateLimit)
+		}
+		base = sdktrace.ParentBased(newRateLimitedSampler(c.SamplerRateLimit, time.Now))
+	default:
+		return nil, fmt.Errorf("unsupported sampler %q", c.Sampler)
+	}
+
+	if len(c.NeverSampleRoutes) == 0 && c.ShouldSample == nil {
+		return base, nil
+	}
+
+	routes := make(map[string]struct{}, len(c.NeverSampleRoutes))
+	for _, r := range c.NeverSampleRoutes {
+		routes[r] = struct{}{}
+	}
+	return &overrideSampler{next: base, routes: routes, hook: c.ShouldSample}, nil
+}
+
+// overrideSampler drops spans for excluded routes or rejected by the hook,
+// and delegates everything else to next.
+type overrideSampler struct {
+	next   sdktrace.Sampler
+	routes map[string]struct{}
+	hook   func(p sdktrace.SamplingParameters) bool
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *overrideSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, attr := range p.Attributes {
+		for _, key := range routeAttributes {
+			if attr.Key != key {
+				continue
+			}
+			if _, ok := s.routes[attr.Value.AsString()]; ok {
+				return dropResult(p)
+			}
+		}
+	}
+	if s.hook != nil && !s.hook(p) {
+		return dropResult(p)
+	}
+	return s.next.ShouldSample(p)
+}
+
+// Description implements sdktrace.Sampler.
+func (s *overrideSampler) Description() string {
+	return fmt.Sprintf("RouteOverride{%s}", s.next.Description())
+}
+
+// rateLimitedSampler samples at most limit spans per second using a token bucket.
+type rateLimitedSampler struct {
+	mtx    sync.Mutex
+	limit  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimitedSampler(limit int, now func() time.Time) *rateLimitedSampler {
+	return &rateLimitedSampler{limit: float64(limit), tokens: float64(limit), last: now(), now: now}
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *rateLimitedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	s.tokens = min(s.limit, s.tokens+now.Sub(s.last).Seconds()*s.limit)
+	s.last = now
+
+	if s.tokens < 1 {
+		return dropResult(p)
+	}
+	s.tokens--
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.RecordAndSample,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler.
+func (s *rateLimitedSampler) Description() string {
+	return fmt.Sprintf("RateLimited{%v}", s.limit)
+}
+
+// dropResult returns a Drop decision preserving the parent trace state.
+func dropResult(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.Drop,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
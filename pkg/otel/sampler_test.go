@@ -0,0 +1,149 @@
+package otel
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+const syntheticSpans = 10000
+
+func randomTraceID() trace.TraceID {
+	var id trace.TraceID
+	for i := range id {
+		id[i] = byte(rand.IntN(256))
+	}
+	return id
+}
+
+// acceptedRootSpans drives n synthetic root spans with attrs through s and counts sampled ones.
+func acceptedRootSpans(s sdktrace.Sampler, n int, attrs ...attribute.KeyValue) int {
+	accepted := 0
+	for i := 0; i < n; i++ {
+		res := s.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: context.Background(),
+			TraceID:       randomTraceID(),
+			Name:          "span",
+			Kind:          trace.SpanKindServer,
+			Attributes:    attrs,
+		})
+		if res.Decision == sdktrace.RecordAndSample {
+			accepted++
+		}
+	}
+	return accepted
+}
+
+func TestNewSampler_AcceptanceRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    Config
+		wantMin int
+		wantMax int
+	}{
+		{"default", Config{}, syntheticSpans, syntheticSpans},
+		{"always on", Config{Sampler: SamplerParentBasedAlwaysOn}, syntheticSpans, syntheticSpans},
+		{"always off", Config{Sampler: SamplerAlwaysOff}, 0, 0},
+		{"ratio 0.25", Config{Sampler: SamplerParentBasedTraceIDRatio, SamplerRatio: 0.25},
+			syntheticSpans * 20 / 100, syntheticSpans * 30 / 100},
+		{"ratio 0", Config{Sampler: SamplerParentBasedTraceIDRatio, SamplerRatio: 0}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newSampler(tt.conf)
+			require.NoError(t, err)
+			got := acceptedRootSpans(s, syntheticSpans)
+			assert.GreaterOrEqual(t, got, tt.wantMin)
+			assert.LessOrEqual(t, got, tt.wantMax)
+		})
+	}
+}
+
+func TestRateLimitedSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newRateLimitedSampler(10, func() time.Time { return now })
+
+	assert.Equal(t, 10, acceptedRootSpans(s, syntheticSpans), "burst should be capped at the limit")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, 5, acceptedRootSpans(s, syntheticSpans), "half a second should refill half the bucket")
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, 10, acceptedRootSpans(s, syntheticSpans), "refill should never exceed the limit")
+}
+
+func TestNewSampler_RateLimitedFollowsParent(t *testing.T) {
+	s, err := newSampler(Config{Sampler: SamplerParentBasedRateLimited, SamplerRateLimit: 1})
+	require.NoError(t, err)
+
+	parent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    randomTraceID(),
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	for i := 0; i < 100; i++ {
+		res := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: parent, TraceID: randomTraceID()})
+		assert.Equal(t, sdktrace.RecordAndSample, res.Decision, "children of sampled parents are not rate limited")
+	}
+}
+
+func TestNewSampler_NeverSampleRoutes(t *testing.T) {
+	s, err := newSampler(Config{NeverSampleRoutes: []string{"/healthz"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, acceptedRootSpans(s, syntheticSpans, semconv.HTTPRoute("/healthz")))
+	assert.Equal(t, 0, acceptedRootSpans(s, syntheticSpans, semconv.URLPath("/healthz")))
+	assert.Equal(t, syntheticSpans, acceptedRootSpans(s, syntheticSpans, semconv.HTTPRoute("/dog/:id")))
+	assert.Equal(t, syntheticSpans, acceptedRootSpans(s, syntheticSpans))
+}
+
+func TestNewSampler_ShouldSampleHook(t *testing.T) {
+	s, err := newSampler(Config{ShouldSample: func(p sdktrace.SamplingParameters) bool {
+		for _, a := range p.Attributes {
+			if a.Key == "internal" && a.Value.AsBool() {
+				return false
+			}
+		}
+		return true
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, acceptedRootSpans(s, 100, attribute.Bool("internal", true)))
+	assert.Equal(t, 100, acceptedRootSpans(s, 100, attribute.Bool("internal", false)))
+}
+
+func TestNewSampler_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Config
+		msg  string
+	}{
+		{"unknown name", Config{Sampler: "sometimes"}, "unsupported sampler"},
+		{"ratio too high", Config{Sampler: SamplerParentBasedTraceIDRatio, SamplerRatio: 1.5}, "within [0, 1]"},
+		{"ratio negative", Config{Sampler: SamplerParentBasedTraceIDRatio, SamplerRatio: -0.1}, "within [0, 1]"},
+		{"zero rate limit", Config{Sampler: SamplerParentBasedRateLimited}, "must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newSampler(tt.conf)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
+
+func TestInit_InvalidSampler(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{Enabled: true, Exporter: ExporterStdout, Sampler: "sometimes"})
+	require.Error(t, err, "invalid sampler names must fail Init")
+	assert.Nil(t, shutdown)
+	assert.Contains(t, err.Error(), "unsupported sampler")
+}
@@ -0,0 +1,69 @@
+package val
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// TestAliases_MatchExpansion verifies that every predefined alias behaves like its expanded expression
+// and that failures report the alias name.
+func TestAliases_MatchExpansion(t *testing.T) {
+	tests := []struct {
+		alias    string
+		expanded string
+		values   []any
+	}{
+		{"host_or_ip", "ip4_addr|hostname_rfc1123", []any{"127.0.0.1", "localhost", "my-service.local", "::1", "not a host", ""}},
+		{"svc_port", "unprivileged_port", []any{1024, 1025, 65535, 65536}},
+		{"log_level", "loglevel", []any{"debug", "error", "trace", ""}},
+		{"log_format", "logformat", []any{"text", "json", "yaml"}},
+	}
+
+	v := GetValidator()
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			for _, value := range tt.values {
+				aliasErr := v.ValidateWithTag(value, tt.alias)
+				expandedErr := v.ValidateWithTag(value, tt.expanded)
+				require.Equal(t, expandedErr == nil, aliasErr == nil, "alias and expansion disagree on %v", value)
+				if aliasErr == nil {
+					continue
+				}
+				var vErr *ValidationError
+				require.True(t, errors.As(aliasErr, &vErr))
+				assert.Equal(t, tt.alias, vErr.Fields()[0].Tag, "expected the alias name to be reported for %v", value)
+			}
+		})
+	}
+}
+
+// TestAliases_Translate verifies that predefined aliases have English messages.
+func TestAliases_Translate(t *testing.T) {
+	type TestStruct struct {
+		Host string `validate:"host_or_ip"`
+		Port int    `validate:"svc_port"`
+	}
+
+	v := GetValidator()
+	err := v.ValidateStruct(TestStruct{Host: "not a host", Port: 80})
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"Host must be a valid IPv4 address or hostname",
+		"Port must be a port between 1025 and 65535",
+	}, v.Translate(err))
+}
+
+// TestRegisterAlias verifies registering a custom alias and the errors for invalid aliases.
+func TestRegisterAlias(t *testing.T) {
+	v := GetValidator()
+	require.NoError(t, v.RegisterAlias("metrics_port", "port,ne=8080"))
+	assert.NoError(t, v.ValidateWithTag(9090, "metrics_port"))
+	assert.Error(t, v.ValidateWithTag(8080, "metrics_port"))
+	assert.Error(t, v.ValidateWithTag(0, "metrics_port"))
+
+	assert.ErrorContains(t, v.RegisterAlias("empty", ""), "tags are empty")
+	assert.ErrorContains(t, v.RegisterAlias("bad,alias", "port"), "failed to register alias bad,alias")
+	assert.Error(t, v.RegisterAlias("omitempty", "port"), "expected restricted tags to be rejected")
+}
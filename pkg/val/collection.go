@@ -0,0 +1,156 @@
+package val
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"sort"
+)
+
+// ValidateSlice validates every struct element of a slice or array, e.g. []genCfg.GrpcConfig.
+// Failures of all elements are aggregated into one *ValidationError whose field paths start
+// with the element index, e.g. "[1].Port". Nil elements fail with the "required" tag.
+// Empty and nil slices are valid. Non-struct elements are rejected; use ValidateSliceWithTag for them.
+// Example usage:
+//
+//	err := validator.ValidateSlice(upstreams)
+func (v *validatorStruct) ValidateSlice(s any) error {
+	return v.ValidateSliceWithTag(s, "")
+}
+
+// ValidateSliceWithTag is like ValidateSlice, validating non-struct elements with tag.
+// Struct elements are validated based on their own tags.
+// Example usage:
+//
+//	err := validator.ValidateSliceWithTag(ports, "port")
+func (v *validatorStruct) ValidateSliceWithTag(s any, tag string) error {
+	val, err := indirect(s, "slice", reflect.Slice, reflect.Array)
+	if err != nil {
+		return err
+	}
+
+	st := v.load()
+	var fields []FieldError
+	for i := 0; i < val.Len(); i++ {
+		elemFields, err := st.validateElem(st.engine, val.Index(i), tag, fmt.Sprintf("[%d]", i))
+		if err != nil {
+			return err
+		}
+		fields = append(fields, elemFields...)
+	}
+	if len(fields) > 0 {
+		return &ValidationError{fields: fields}
+	}
+	return nil
+}
+
+// ValidateMap validates every struct value of a map, e.g. map[string]genCfg.GrpcConfig.
+// Failures of all values are aggregated into one *ValidationError whose field paths start
+// with the key, e.g. "[primary].Port"; keys are visited in sorted order. Nil values fail with
+// the "required" tag. Empty and nil maps are valid. Non-struct values are rejected; use
+// ValidateMapWithTag for them.
+// Example usage:
+//
+//	err := validator.ValidateMap(upstreams)
+func (v *validatorStruct) ValidateMap(m any) error {
+	return v.ValidateMapWithTag(m, "")
+}
+
+// ValidateMapWithTag is like ValidateMap, validating non-struct values with tag.
+// Struct values are validated based on their own tags.
+// Example usage:
+//
+//	err := validator.ValidateMapWithTag(endpoints, "url")
+func (v *validatorStruct) ValidateMapWithTag(m any, tag string) error {
+	val, err := indirect(m, "map", reflect.Map)
+	if err != nil {
+		return err
+	}
+
+	st := v.load()
+	keys := val.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	var fields []FieldError
+	for _, key := range keys {
+		elemFields, err := st.validateElem(st.engine, val.MapIndex(key), tag, fmt.Sprintf("[%v]", key.Interface()))
+		if err != nil {
+			return err
+		}
+		fields = append(fields, elemFields...)
+	}
+	if len(fields) > 0 {
+		return &ValidationError{fields: fields}
+	}
+	return nil
+}
+
+// validateElem validates a single element of a collection with e and returns its failed fields
+// with paths starting with prefix. Errors other than failed rules are returned as error.
+func (s *state) validateElem(e *engine, elem reflect.Value, tag, prefix string) ([]FieldError, error) {
+	for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+		if elem.IsNil() {
+			return []FieldError{{Field: prefix, Name: prefix, Tag: "required"}}, nil
+		}
+		elem = elem.Elem()
+	}
+
+	ctx, rec := s.withPanicRecorder(context.Background())
+	var err error
+	switch {
+	case elem.Kind() == reflect.Struct:
+		err = e.validate.StructCtx(ctx, elem.Interface())
+	case tag != "":
+		err = e.validate.VarCtx(ctx, elem.Interface(), tag)
+	default:
+		return nil, fmt.Errorf("element %s is not a struct, got: %s", prefix, elem.Kind())
+	}
+	if err == nil {
+		return nil, nil
+	}
+
+	var valErr validator.ValidationErrors
+	if !errors.As(err, &valErr) {
+		return nil, handleValidatorError(err, e.trans)
+	}
+	fields := newValidationError(valErr, e.trans).fields
+	rec.markFields(fields)
+	for i := range fields {
+		fields[i].Field = joinPath(prefix, fields[i].Field)
+		fields[i].Name = joinPath(prefix, fields[i].Name)
+	}
+	return fields, nil
+}
+
+// indirect dereferences pointers to the input and checks that it has one of kinds, described as what.
+// Like validateStruct, it rejects nil inputs and nil pointers.
+func indirect(in any, what string, kinds ...reflect.Kind) (reflect.Value, error) {
+	val := reflect.ValueOf(in)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return reflect.Value{}, fmt.Errorf("input is a nil pointer")
+		}
+		val = val.Elem()
+	}
+	for _, kind := range kinds {
+		if val.Kind() == kind {
+			return val, nil
+		}
+	}
+	if in == nil {
+		return reflect.Value{}, fmt.Errorf("input is nil")
+	}
+	return reflect.Value{}, fmt.Errorf("input is not a %s, got: %s", what, val.Kind())
+}
+
+// joinPath appends the field path to the element prefix.
+func joinPath(prefix, path string) string {
+	if prefix == "" || path == "" {
+		return prefix + path
+	}
+	return prefix + "." + path
+}
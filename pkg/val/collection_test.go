@@ -0,0 +1,98 @@
+package val
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type collectionTestUpstream struct {
+	Host string `mapstructure:"grpc_host" validate:"host_or_ip,required"`
+	Port int    `mapstructure:"grpc_port" validate:"svc_port,required"`
+}
+
+// TestValidateSlice verifies the aggregated errors of mixed valid and invalid elements.
+func TestValidateSlice(t *testing.T) {
+	v := GetValidator()
+	assert.NoError(t, v.ValidateSlice([]collectionTestUpstream{{Host: "localhost", Port: 50051}}))
+
+	err := v.ValidateSlice([]*collectionTestUpstream{
+		{Host: "localhost", Port: 50051},
+		{Host: "localhost", Port: 80},
+		nil,
+		{Port: 50052},
+	})
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	assert.Equal(t, []FieldError{
+		{Field: "[1].Port", Name: "[1].grpc_port", Tag: "svc_port", Value: 80},
+		{Field: "[2]", Name: "[2]", Tag: "required"},
+		{Field: "[3].Host", Name: "[3].grpc_host", Tag: "host_or_ip", Value: ""},
+	}, withoutErrs(vErr.Fields()))
+	assert.Contains(t, err.Error(), "Field '[1].Port'")
+}
+
+// TestValidateSlice_Empty verifies that empty and nil slices are valid.
+func TestValidateSlice_Empty(t *testing.T) {
+	v := GetValidator()
+	assert.NoError(t, v.ValidateSlice([]collectionTestUpstream{}))
+	assert.NoError(t, v.ValidateSlice([]collectionTestUpstream(nil)))
+	assert.NoError(t, v.ValidateSlice(&[0]collectionTestUpstream{}))
+}
+
+// TestValidateSlice_InvalidInput verifies the errors for inputs that aren't slices of structs.
+func TestValidateSlice_InvalidInput(t *testing.T) {
+	v := GetValidator()
+	assert.ErrorContains(t, v.ValidateSlice(nil), "input is nil")
+	assert.ErrorContains(t, v.ValidateSlice((*[]collectionTestUpstream)(nil)), "input is a nil pointer")
+	assert.ErrorContains(t, v.ValidateSlice(collectionTestUpstream{}), "input is not a slice, got: struct")
+	assert.ErrorContains(t, v.ValidateSlice([]int{1}), "element [0] is not a struct, got: int")
+}
+
+// TestValidateSliceWithTag verifies that non-struct elements fall back to tag validation.
+func TestValidateSliceWithTag(t *testing.T) {
+	v := GetValidator()
+	assert.NoError(t, v.ValidateSliceWithTag([]int{1, 8080, 65535}, "port"))
+
+	err := v.ValidateSliceWithTag([]any{8080, 0, &collectionTestUpstream{Host: "localhost", Port: 1}}, "port")
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	assert.Equal(t, []FieldError{
+		{Field: "[1]", Name: "[1]", Tag: "port", Value: 0},
+		{Field: "[2].Port", Name: "[2].grpc_port", Tag: "svc_port", Value: 1},
+	}, withoutErrs(vErr.Fields()))
+}
+
+// TestValidateMap verifies the aggregated errors of map values keyed by the map key.
+func TestValidateMap(t *testing.T) {
+	v := GetValidator()
+	assert.NoError(t, v.ValidateMap(map[string]collectionTestUpstream{}))
+	assert.NoError(t, v.ValidateMap(map[string]collectionTestUpstream(nil)))
+
+	err := v.ValidateMap(map[string]*collectionTestUpstream{
+		"primary":   {Host: "localhost", Port: 50051},
+		"secondary": {Host: "localhost", Port: 70000},
+		"backup":    nil,
+	})
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	assert.Equal(t, []FieldError{
+		{Field: "[backup]", Name: "[backup]", Tag: "required"},
+		{Field: "[secondary].Port", Name: "[secondary].grpc_port", Tag: "svc_port", Value: 70000},
+	}, withoutErrs(vErr.Fields()))
+
+	assert.ErrorContains(t, v.ValidateMap([]collectionTestUpstream{}), "input is not a map, got: slice")
+	assert.ErrorContains(t, v.ValidateMap(map[string]string{"a": "b"}), "element [a] is not a struct, got: string")
+}
+
+// TestValidateMapWithTag verifies that non-struct values fall back to tag validation.
+func TestValidateMapWithTag(t *testing.T) {
+	v := GetValidator()
+	err := v.ValidateMapWithTag(map[string]string{"api": "https://example.com", "db": "ftp://example.com"}, "urlprefix")
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	assert.Equal(t, []FieldError{
+		{Field: "[db]", Name: "[db]", Tag: "urlprefix", Value: "ftp://example.com"},
+	}, withoutErrs(vErr.Fields()))
+}
@@ -0,0 +1,41 @@
+package val
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type tenantKey struct{}
+
+// TestValidateCtx verifies that a ctx-aware validator sees the context passed to the Ctx methods.
+func TestValidateCtx(t *testing.T) {
+	v := GetValidator()
+	require.NoError(t, v.RegisterValidationCtx("tenant-owned", func(ctx context.Context, fl validator.FieldLevel) bool {
+		return fl.Field().String() == ctx.Value(tenantKey{})
+	}))
+
+	type TestStruct struct {
+		Tenant string `validate:"tenant-owned"`
+	}
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	assert.NoError(t, v.ValidateStructCtx(ctx, TestStruct{Tenant: "acme"}))
+	assert.NoError(t, v.ValidateWithTagCtx(ctx, "acme", "tenant-owned"))
+
+	err := v.ValidateStructCtx(ctx, TestStruct{Tenant: "globex"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tenant-owned")
+	assert.Error(t, v.ValidateWithTagCtx(ctx, "globex", "tenant-owned"))
+
+	assert.Error(t, v.ValidateStruct(TestStruct{Tenant: "acme"}), "expected the non-ctx method to pass an empty context")
+	assert.Error(t, v.ValidateWithTag("acme", "tenant-owned"), "expected the non-ctx method to pass an empty context")
+}
+
+// TestValidateStructCtx_UnsupportedType ensures that ValidateStructCtx performs the same input checks as ValidateStruct.
+func TestValidateStructCtx_UnsupportedType(t *testing.T) {
+	err := GetValidator().ValidateStructCtx(context.Background(), 123)
+	assert.ErrorContains(t, err, "input is not a struct")
+}
@@ -0,0 +1,137 @@
+package val
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hostnameRFC1123 matches hostnames as the built-in "hostname_rfc1123" tag does.
+var hostnameRFC1123 = regexp.MustCompile(`^([a-zA-Z0-9]{1}[a-zA-Z0-9-]{0,62}){1}(\.[a-zA-Z0-9]{1}[a-zA-Z0-9-]{0,62})*?$`)
+
+// portBetween returns a validation function accepting integer or numeric string fields within [lo, hi].
+func portBetween(lo, hi int64) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		field := fl.Field()
+		var port int64
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			port = field.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if field.Uint() > uint64(hi) {
+				return false
+			}
+			port = int64(field.Uint())
+		case reflect.String:
+			p, err := strconv.ParseInt(field.String(), 10, 64)
+			if err != nil {
+				return false
+			}
+			port = p
+		default:
+			return false
+		}
+		return port >= lo && port <= hi
+	}
+}
+
+// oneOf returns a validation function accepting string fields equal to one of allowed.
+func oneOf(allowed ...string) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		field := fl.Field()
+		return field.Kind() == reflect.String && slices.Contains(allowed, field.String())
+	}
+}
+
+// durationBetween validates that a time.Duration field, or a string parsed with time.ParseDuration,
+// lies within the inclusive range given by the "min:max" tag parameter, e.g. "duration_between=100ms:30s".
+// Like the built-in validators, it panics on a malformed parameter since that is a programming error.
+func durationBetween(fl validator.FieldLevel) bool {
+	lo, hi, err := parseDurationRange(fl.Param())
+	if err != nil {
+		panic(fmt.Sprintf("invalid duration_between parameter: %v", err))
+	}
+
+	field := fl.Field()
+	var d time.Duration
+	switch field.Kind() {
+	case reflect.Int64:
+		d = time.Duration(field.Int())
+	case reflect.String:
+		if d, err = time.ParseDuration(field.String()); err != nil {
+			return false
+		}
+	default:
+		return false
+	}
+	return d >= lo && d <= hi
+}
+
+// parseDurationRange parses a "min:max" duration range such as "100ms:30s".
+func parseDurationRange(param string) (time.Duration, time.Duration, error) {
+	loStr, hiStr, ok := strings.Cut(param, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("%q is not in min:max form", param)
+	}
+	lo, err := time.ParseDuration(loStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse min of %q: %w", param, err)
+	}
+	hi, err := time.ParseDuration(hiStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse max of %q: %w", param, err)
+	}
+	if lo > hi {
+		return 0, 0, fmt.Errorf("min of %q is greater than max", param)
+	}
+	return lo, hi, nil
+}
+
+// uriScheme validates that a string field parses as a URI whose scheme is one of the
+// space-separated schemes of the tag parameter, compared case-insensitively,
+// e.g. "uri_scheme=postgresql postgres sqlite file".
+// Like the built-in validators, it panics on an empty parameter since that is a programming error.
+func uriScheme(fl validator.FieldLevel) bool {
+	allowed := strings.Fields(fl.Param())
+	if len(allowed) == 0 {
+		panic("invalid uri_scheme parameter: no schemes given")
+	}
+
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return false
+	}
+	u, err := url.Parse(field.String())
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	return slices.ContainsFunc(allowed, func(scheme string) bool {
+		return strings.EqualFold(scheme, u.Scheme)
+	})
+}
+
+// listenAddr validates that a string field is a "host:port" address as accepted by net.Listen,
+// e.g. "0.0.0.0:8080", "[::1]:9090" or ":8080". The host must be an IPv4 or IPv6 address or
+// an RFC 1123 hostname, or empty to listen on all interfaces; the port must be 1-65535.
+func listenAddr(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return false
+	}
+	host, port, err := net.SplitHostPort(field.String())
+	if err != nil {
+		return false
+	}
+	if host != "" && net.ParseIP(host) == nil && !hostnameRFC1123.MatchString(host) {
+		return false
+	}
+	p, err := strconv.Atoi(port)
+	return err == nil && p >= 1 && p <= 65535
+}
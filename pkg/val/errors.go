@@ -0,0 +1,150 @@
+package val
+
+import (
+	"encoding/json"
+	"fmt"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a single failed validation rule.
+type FieldError struct {
+	Field string `json:"field"` // Go field path below the validated struct, e.g. "Port" or "DB.URI"; empty for ValidateWithTag
+	Name  string `json:"name"`  // Path of json, then mapstructure, then Go names, e.g. "upstreams[0].grpc_port"
+	Tag   string `json:"tag"`   // Failed tag, e.g. "unprivileged_port"
+	Param string `json:"param"` // Tag parameter, e.g. "100ms:30s"; empty if the tag takes none
+	Value any    `json:"-"`     // Actual value of the field; not encoded to JSON, as it may be a secret
+
+	err   validator.FieldError // Underlying validator error, used for messages and translation
+	trans ut.Translator        // Translator of the engine that reported err
+}
+
+// Error returns a human-readable description of the failure.
+func (fe FieldError) Error() string {
+	if fe.err == nil {
+		return fmt.Sprintf("Field '%s': failed on the '%s' tag", fe.Field, fe.Tag)
+	}
+	if fe.Tag == InternalValidationErrorTag {
+		return fmt.Sprintf("Field '%s': validation function of the '%s' tag panicked", fe.Field, fe.err.ActualTag())
+	}
+	return fmt.Sprintf("Field '%s': %s", fe.Field, fe.err.Error())
+}
+
+// ValidationError is returned by ValidateStruct and ValidateWithTag when one or more rules fail.
+// Its Error method keeps the message readable for logs, while Fields and MarshalJSON expose
+// the failures to callers building API responses.
+//
+// Example usage:
+//
+//	var vErr *val.ValidationError
+//	if errors.As(err, &vErr) {
+//	    c.JSON(http.StatusBadRequest, vErr)
+//	}
+type ValidationError struct {
+	fields []FieldError
+}
+
+// Error joins the description of every failed field.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.fields))
+	for _, fe := range e.fields {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// Fields returns a copy of the failed fields in the order the validator reported them.
+func (e *ValidationError) Fields() []FieldError {
+	out := make([]FieldError, len(e.fields))
+	copy(out, e.fields)
+	return out
+}
+
+// Unwrap returns the underlying validator.ValidationErrors, so errors.As matches code handling
+// the go-playground errors directly. Fields without an underlying error, such as nil elements
+// of ValidateSlice, are left out.
+func (e *ValidationError) Unwrap() error {
+	valErr := make(validator.ValidationErrors, 0, len(e.fields))
+	for _, fe := range e.fields {
+		if fe.err != nil {
+			valErr = append(valErr, fe.err)
+		}
+	}
+	return valErr
+}
+
+// MarshalJSON encodes the error as {"errors": [...]} with one object per failed field.
+// Field values are left out, so the response never echoes a rejected password or token.
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	fields := e.fields
+	if fields == nil {
+		fields = []FieldError{}
+	}
+	return json.Marshal(struct {
+		Errors []FieldError `json:"errors"`
+	}{Errors: fields})
+}
+
+// newValidationError converts the validator errors into a ValidationError translated with trans.
+func newValidationError(valErr validator.ValidationErrors, trans ut.Translator) *ValidationError {
+	fields := make([]FieldError, 0, len(valErr))
+	for _, fe := range valErr {
+		fields = append(fields, FieldError{
+			Field: fieldPath(fe.StructNamespace()),
+			Name:  fieldPath(fe.Namespace()),
+			Tag:   fe.Tag(),
+			Param: fe.Param(),
+			Value: fe.Value(),
+			err:   fe,
+			trans: trans,
+		})
+	}
+	return &ValidationError{fields: fields}
+}
+
+// fieldPath strips the top-level struct name from namespace, e.g. "HttpConfig.Port" becomes "Port".
+// Namespaces of ValidateWithTag errors carry no struct name and yield an empty path.
+func fieldPath(namespace string) string {
+	_, path, ok := strings.Cut(namespace, ".")
+	if !ok {
+		return ""
+	}
+	return path
+}
+
+// fieldTagName returns the json name of sf, then its mapstructure name, then its Go name.
+// It is registered with the validator, which names fields in errors and translations after it.
+func fieldTagName(sf reflect.StructField) string {
+	for _, key := range []string{"json", "mapstructure"} {
+		name, _, _ := strings.Cut(sf.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// structType dereferences pointers and returns t if it is a struct type, nil otherwise.
+func structType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// elemType returns the element type of slices, arrays and maps, dereferencing pointers first.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package val
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type errTestUpstream struct {
+	Port int `mapstructure:"grpc_port" validate:"port"`
+}
+
+type errTestConfig struct {
+	Host      string            `json:"host" mapstructure:"http_host" validate:"required"`
+	Port      int               `mapstructure:"http_port" validate:"unprivileged_port"`
+	Level     string            `validate:"loglevel"`
+	Upstreams []errTestUpstream `json:"upstreams" validate:"dive"`
+}
+
+// TestValidationError_Fields verifies that ValidateStruct returns a ValidationError describing every failed field.
+func TestValidationError_Fields(t *testing.T) {
+	err := GetValidator().ValidateStruct(&errTestConfig{
+		Port:      80,
+		Level:     "trace",
+		Upstreams: []errTestUpstream{{Port: 50051}, {Port: 0}},
+	})
+	require.Error(t, err)
+
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	assert.Equal(t, []FieldError{
+		{Field: "Host", Name: "host", Tag: "required", Value: ""},
+		{Field: "Port", Name: "http_port", Tag: "unprivileged_port", Value: 80},
+		{Field: "Level", Name: "Level", Tag: "loglevel", Value: "trace"},
+		{Field: "Upstreams[1].Port", Name: "upstreams[1].grpc_port", Tag: "port", Value: 0},
+	}, withoutErrs(vErr.Fields()))
+
+	assert.Contains(t, err.Error(), "Field 'Port'")
+	assert.Contains(t, err.Error(), "failed on the 'unprivileged_port' tag")
+}
+
+// TestValidationError_WithTag verifies the ValidationError returned by ValidateWithTag.
+func TestValidationError_WithTag(t *testing.T) {
+	err := GetValidator().ValidateWithTag(99, "duration_between=100ms:1s")
+
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	require.Len(t, vErr.Fields(), 1)
+	fe := vErr.Fields()[0]
+	assert.Empty(t, fe.Field)
+	assert.Equal(t, "duration_between", fe.Tag)
+	assert.Equal(t, "100ms:1s", fe.Param)
+	assert.Equal(t, 99, fe.Value)
+}
+
+// TestValidationError_MarshalJSON verifies the JSON representation handed to API consumers.
+func TestValidationError_MarshalJSON(t *testing.T) {
+	err := GetValidator().ValidateStruct(errTestConfig{Host: "localhost", Port: 1024, Level: "info"})
+
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr))
+	data, mErr := json.Marshal(vErr)
+	require.NoError(t, mErr)
+	assert.JSONEq(t, `{"errors":[{"field":"Port","name":"http_port","tag":"unprivileged_port","param":""}]}`, string(data))
+
+	data, mErr = json.Marshal(&ValidationError{})
+	require.NoError(t, mErr)
+	assert.JSONEq(t, `{"errors":[]}`, string(data))
+}
+
+// TestValidationError_FieldsCopy ensures that modifying the result of Fields doesn't affect the error.
+func TestValidationError_FieldsCopy(t *testing.T) {
+	vErr := &ValidationError{fields: []FieldError{{Field: "Port"}}}
+	vErr.Fields()[0].Field = "changed"
+	assert.Equal(t, "Port", vErr.Fields()[0].Field)
+}
+
+func withoutErrs(fields []FieldError) []FieldError {
+	for i := range fields {
+		fields[i].err, fields[i].trans = nil, nil
+	}
+	return fields
+}
+
+// TestValidationError_Names verifies that field names prefer the json tag, then the mapstructure tag, then the Go name.
+func TestValidationError_Names(t *testing.T) {
+	type TestStruct struct {
+		JSONOnly         int `json:"json_only,omitempty" validate:"port"`
+		MapstructureOnly int `mapstructure:"mapstructure_only" validate:"port"`
+		Both             int `json:"both_json" mapstructure:"both_mapstructure" validate:"port"`
+		Skipped          int `json:"-" mapstructure:"skipped" validate:"port"`
+		Untagged         int `validate:"port"`
+	}
+
+	v := GetValidator()
+	err := v.ValidateStruct(TestStruct{})
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+
+	var fields, names []string
+	for _, fe := range vErr.Fields() {
+		fields = append(fields, fe.Field)
+		names = append(names, fe.Name)
+	}
+	assert.Equal(t, []string{"JSONOnly", "MapstructureOnly", "Both", "Skipped", "Untagged"}, fields)
+	assert.Equal(t, []string{"json_only", "mapstructure_only", "both_json", "skipped", "Untagged"}, names)
+
+	msgs := v.Translate(err)
+	require.Len(t, msgs, 5)
+	assert.Equal(t, "json_only must be a port between 1 and 65535", msgs[0], "expected translations to use the resolved name")
+	assert.Equal(t, "Untagged must be a port between 1 and 65535", msgs[4])
+}
+
+// TestValidationError_Unwrap verifies that errors.As finds the underlying validator errors.
+func TestValidationError_Unwrap(t *testing.T) {
+	err := GetValidator().ValidateStruct(errTestConfig{Host: "localhost", Port: 1024, Level: "trace"})
+
+	var valErr validator.ValidationErrors
+	require.True(t, errors.As(err, &valErr), "expected errors.As to find validator.ValidationErrors")
+	require.Len(t, valErr, 2)
+	assert.Equal(t, "unprivileged_port", valErr[0].Tag())
+	assert.Equal(t, "loglevel", valErr[1].Tag())
+}
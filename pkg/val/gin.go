@@ -0,0 +1,95 @@
+package val
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"reflect"
+)
+
+// ginValidator adapts the singleton to gin's binding.StructValidator.
+type ginValidator struct {
+	v *validatorStruct
+}
+
+// UseWithGin installs the singleton validator as gin's binding validator, so ShouldBind and
+// friends check both `binding` and `validate` tags with the custom tags, aliases and structured
+// errors of the rest of the application. Call it once during startup, before serving requests.
+//
+// Example usage:
+//
+//	val.UseWithGin()
+//
+//	type CreateServer struct {
+//	    Port int `json:"port" binding:"required,port"`
+//	}
+//
+//	if err := c.ShouldBindJSON(&req); err != nil {
+//	    c.JSON(http.StatusBadRequest, err)
+//	}
+func UseWithGin() {
+	GetValidator()
+	binding.Validator = &ginValidator{v: singleton}
+}
+
+// ValidateStruct validates structs, pointers to structs and slices of structs like gin's
+// default validator, and ignores any other type.
+func (g *ginValidator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return g.v.validateBinding([]reflect.Value{value}, false)
+	case reflect.Slice, reflect.Array:
+		if structType(elemType(value.Type())) != nil {
+			elems := make([]reflect.Value, value.Len())
+			for i := range elems {
+				elems[i] = value.Index(i)
+			}
+			return g.v.validateBinding(elems, true)
+		}
+	}
+	return nil
+}
+
+// Engine returns the underlying *validator.Validate reading the validate tag.
+func (g *ginValidator) Engine() any {
+	return g.v.Engine()
+}
+
+// validateBinding validates elems with the validate and the binding engines and aggregates the
+// failures of both into one *ValidationError. Field paths start with the element index if indexed.
+func (v *validatorStruct) validateBinding(elems []reflect.Value, indexed bool) error {
+	st := v.load()
+	var fields []FieldError
+	for i, elem := range elems {
+		prefix := ""
+		if indexed {
+			prefix = fmt.Sprintf("[%d]", i)
+		}
+		if (elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface) && elem.IsNil() {
+			fields = append(fields, FieldError{Field: prefix, Name: prefix, Tag: "required"})
+			continue
+		}
+		for _, e := range st.engines() {
+			elemFields, err := st.validateElem(e, elem, "", prefix)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, elemFields...)
+		}
+	}
+	if len(fields) > 0 {
+		return &ValidationError{fields: fields}
+	}
+	return nil
+}
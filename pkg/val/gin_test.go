@@ -0,0 +1,132 @@
+package val
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type ginTestServer struct {
+	Name string `json:"name" validate:"required"`
+	Port int    `json:"port" validate:"port"`
+}
+
+type ginTestBinding struct {
+	Name  string `json:"name" binding:"required"`
+	Port  int    `json:"port" binding:"omitempty,svc_port"`
+	Level string `json:"level" validate:"omitempty,log_level"`
+}
+
+// TestUseWithGin verifies that gin binds request bodies with the shared engine and custom tags.
+func TestUseWithGin(t *testing.T) {
+	prev := binding.Validator
+	defer func() { binding.Validator = prev }()
+	UseWithGin()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/servers", func(c *gin.Context) {
+		var req ginTestServer
+		if err := c.ShouldBindJSON(&req); err != nil {
+			var vErr *ValidationError
+			if errors.As(err, &vErr) {
+				c.JSON(http.StatusBadRequest, vErr)
+				return
+			}
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/servers", strings.NewReader(`{"name":"api","port":8080}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/servers", strings.NewReader(`{"name":"api","port":70000}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"errors":[{"field":"Port","name":"port","tag":"port","param":""}]}`, w.Body.String())
+}
+
+// TestGinValidator_Types verifies the input types handled by the gin adapter.
+func TestGinValidator_Types(t *testing.T) {
+	g := &ginValidator{v: GetValidator().(*validatorStruct)}
+
+	assert.NoError(t, g.ValidateStruct(nil))
+	assert.NoError(t, g.ValidateStruct((*ginTestServer)(nil)))
+	assert.NoError(t, g.ValidateStruct(map[string]any{"port": 0}), "expected maps to be ignored")
+	assert.NoError(t, g.ValidateStruct([]int{0}), "expected slices of non-structs to be ignored")
+	assert.Error(t, g.ValidateStruct(ginTestServer{Port: 80}))
+	assert.Error(t, g.ValidateStruct(&ginTestServer{Port: 80}))
+	assert.ErrorContains(t, g.ValidateStruct([]*ginTestServer{{Name: "api", Port: 80}, {Port: 80}}), "Field '[1].Name'")
+
+	engine, ok := g.Engine().(*validator.Validate)
+	require.True(t, ok)
+	assert.Same(t, GetValidator().Engine(), engine)
+}
+
+// TestUseWithGin_BindingTags verifies that binding tags, including custom tags and aliases, are still checked.
+func TestUseWithGin_BindingTags(t *testing.T) {
+	prev := binding.Validator
+	defer func() { binding.Validator = prev }()
+	UseWithGin()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/servers", func(c *gin.Context) {
+		var req ginTestBinding
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	tests := []struct {
+		name string
+		body string
+		code int
+		want string
+	}{
+		{"valid", `{"name":"api","port":8080,"level":"info"}`, http.StatusCreated, ""},
+		{"binding required", `{"port":8080}`, http.StatusBadRequest,
+			`{"errors":[{"field":"Name","name":"name","tag":"required","param":""}]}`},
+		{"binding custom alias", `{"name":"api","port":80}`, http.StatusBadRequest,
+			`{"errors":[{"field":"Port","name":"port","tag":"svc_port","param":""}]}`},
+		{"both tags", `{"level":"trace"}`, http.StatusBadRequest,
+			`{"errors":[{"field":"Level","name":"level","tag":"log_level","param":""},{"field":"Name","name":"name","tag":"required","param":""}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/servers", strings.NewReader(tt.body))
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code)
+			if tt.want != "" {
+				assert.JSONEq(t, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestGinValidator_BindingTranslate verifies that failures of binding tags are translated.
+func TestGinValidator_BindingTranslate(t *testing.T) {
+	g := &ginValidator{v: GetValidator().(*validatorStruct)}
+	err := g.ValidateStruct(&ginTestBinding{Port: 80})
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"name is a required field",
+		"port must be a port between 1025 and 65535",
+	}, GetValidator().Translate(err))
+	assert.ErrorContains(t, g.ValidateStruct([]*ginTestBinding{{Name: "api"}, nil}), "Field '[1]'")
+}
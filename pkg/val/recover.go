@@ -0,0 +1,171 @@
+package val
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// InternalValidationErrorTag is the tag of fields whose validation function panicked
+// while panic recovery is enabled.
+const InternalValidationErrorTag = "internal_validation_error"
+
+// Option configures the singleton validator.
+type Option func(s *state)
+
+// WithPanicRecovery converts panics of the package's custom validators and of validation functions
+// registered with RegisterValidation or RegisterValidationCtx into a failure of that field tagged
+// InternalValidationErrorTag, instead of letting the panic take down the caller.
+// Built-in go-playground tags are not covered.
+func WithPanicRecovery() Option {
+	return func(s *state) {
+		s.recoverPanics = true
+	}
+}
+
+// WithLogger sets the logger reporting recovered panics. Panics are not logged without it.
+func WithLogger(lg *slog.Logger) Option {
+	return func(s *state) {
+		s.logger = lg
+	}
+}
+
+// Configure applies options to the singleton validator, replacing any earlier configuration.
+// Without options the validator fails fast: panics of validation functions propagate to the caller.
+// Validations in flight keep the configuration they started with.
+//
+// Example usage:
+//
+//	val.Configure(val.WithPanicRecovery(), val.WithLogger(logger))
+func Configure(options ...Option) {
+	GetValidator()
+	singleton.mtx.Lock()
+	defer singleton.mtx.Unlock()
+
+	cur := singleton.state.Load()
+	next := &state{engine: cur.engine, binding: cur.binding}
+	for _, option := range options {
+		option(next)
+	}
+	singleton.state.Store(next)
+}
+
+// panicsKey is the context key of the panicRecorder of a validation.
+type panicsKey struct{}
+
+// recoveredPanic identifies the field whose validation function panicked. The validator does not
+// expose the namespace to validation functions, so the field is identified by the last element of
+// its namespace, e.g. "Values[2]", its struct field name and its value.
+type recoveredPanic struct {
+	tag         string
+	field       string
+	structField string
+	value       any
+}
+
+// panicRecorder collects the panics recovered during a single validation.
+type panicRecorder struct {
+	logger *slog.Logger
+	panics []recoveredPanic
+}
+
+// withPanicRecorder returns ctx carrying a new panicRecorder if panic recovery is enabled,
+// and ctx with a nil recorder otherwise.
+func (s *state) withPanicRecorder(ctx context.Context) (context.Context, *panicRecorder) {
+	if !s.recoverPanics {
+		return ctx, nil
+	}
+	rec := &panicRecorder{logger: s.logger}
+	return context.WithValue(ctx, panicsKey{}, rec), rec
+}
+
+// withoutCtx adapts fn to validator.FuncCtx. A nil fn yields nil, which the validator rejects.
+func withoutCtx(fn validator.Func) validator.FuncCtx {
+	if fn == nil {
+		return nil
+	}
+	return func(_ context.Context, fl validator.FieldLevel) bool {
+		return fn(fl)
+	}
+}
+
+// recoverable wraps fn so that, when the validation context carries a panicRecorder, a panic
+// is recorded and the field fails instead of the panic propagating.
+func recoverable(fn validator.FuncCtx) validator.FuncCtx {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context, fl validator.FieldLevel) (ok bool) {
+		rec, _ := ctx.Value(panicsKey{}).(*panicRecorder)
+		if rec == nil {
+			return fn(ctx, fl)
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				rec.record(fl, r)
+				ok = false
+			}
+		}()
+		return fn(ctx, fl)
+	}
+}
+
+// record stores the panic of the validation function of fl and logs it if a logger is configured.
+func (rec *panicRecorder) record(fl validator.FieldLevel, r any) {
+	var value any
+	if fl.Field().CanInterface() {
+		value = fl.Field().Interface()
+	}
+	rec.panics = append(rec.panics, recoveredPanic{
+		tag:         fl.GetTag(),
+		field:       fl.FieldName(),
+		structField: fl.StructFieldName(),
+		value:       value,
+	})
+	if rec.logger != nil {
+		rec.logger.Error("validation function panicked",
+			"tag", fl.GetTag(), "field", fl.StructFieldName(), "panic", fmt.Sprint(r))
+	}
+}
+
+// mark retags the fields of err whose validation function panicked. A nil recorder leaves err unchanged.
+func (rec *panicRecorder) mark(err error) error {
+	var vErr *ValidationError
+	if rec != nil && errors.As(err, &vErr) {
+		rec.markFields(vErr.fields)
+	}
+	return err
+}
+
+// markFields retags fields whose validation function panicked with InternalValidationErrorTag.
+// Each failed field is matched against the recorded panics of its field, struct field and value,
+// whatever their order: a panicking alternative of a "|" tag fails no field if another alternative
+// passes, and its panic must not be attributed to a later field. The failure of a "|" tag matches
+// the panics of any of its alternatives.
+func (rec *panicRecorder) markFields(fields []FieldError) {
+	if rec == nil {
+		return
+	}
+	matched := make([]bool, len(rec.panics))
+	for i := range fields {
+		fe := fields[i].err
+		if fe == nil {
+			continue
+		}
+		alternatives := strings.Split(fe.ActualTag(), "|")
+		for j, p := range rec.panics {
+			if matched[j] || !slices.Contains(alternatives, p.tag) || fe.Field() != p.field ||
+				fe.StructField() != p.structField || !reflect.DeepEqual(fe.Value(), p.value) {
+				continue
+			}
+			fields[i].Tag = InternalValidationErrorTag
+			matched[j] = true
+			break
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package val
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type timeoutsConfig struct {
+	ReadTimeout     time.Duration `mapstructure:"read_timeout" validate:"duration_between=100ms:1s"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout" validate:"duration_between=100ms:1s"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+}
+
+// TestRegisterStructValidation verifies a cross-field rule and its structured error.
+func TestRegisterStructValidation(t *testing.T) {
+	v := GetValidator()
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		conf := sl.Current().Interface().(timeoutsConfig)
+		if conf.ReadTimeout+conf.WriteTimeout > conf.ShutdownTimeout {
+			sl.ReportError(conf.ShutdownTimeout, "shutdown_timeout", "ShutdownTimeout", "timeouts_sum", "")
+		}
+	}, timeoutsConfig{})
+
+	assert.NoError(t, v.ValidateStruct(timeoutsConfig{ReadTimeout: time.Second, WriteTimeout: time.Second, ShutdownTimeout: 2 * time.Second}))
+
+	err := v.ValidateStruct(&timeoutsConfig{ReadTimeout: time.Second, WriteTimeout: time.Second, ShutdownTimeout: time.Second})
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	assert.Equal(t, []FieldError{
+		{Field: "ShutdownTimeout", Name: "shutdown_timeout", Tag: "timeouts_sum", Value: time.Second},
+	}, withoutErrs(vErr.Fields()))
+
+	err = v.ValidateStruct(timeoutsConfig{ReadTimeout: time.Minute, WriteTimeout: time.Second, ShutdownTimeout: time.Second})
+	require.True(t, errors.As(err, &vErr))
+	fields := withoutErrs(vErr.Fields())
+	assert.Len(t, fields, 2, "expected tag and struct-level errors to be reported together")
+	assert.Equal(t, "duration_between", fields[0].Tag)
+	assert.Equal(t, "timeouts_sum", fields[1].Tag)
+}
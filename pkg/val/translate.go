@@ -0,0 +1,145 @@
+package val
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entrans "github.com/go-playground/validator/v10/translations/en"
+	"strings"
+)
+
+// customTranslations holds the English messages of the custom validators and aliases, where {0} is the field name.
+var customTranslations = map[string]string{
+	"urlprefix":         "{0} must start with http:// or https://",
+	"port":              "{0} must be a port between 1 and 65535",
+	"unprivileged_port": "{0} must be a port between 1025 and 65535",
+	"loglevel":          "{0} must be one of [debug info warn error]",
+	"logformat":         "{0} must be one of [text json]",
+	"listen_addr":       "{0} must be a valid host:port address",
+	"host_or_ip":        "{0} must be a valid IPv4 address or hostname",
+	"svc_port":          "{0} must be a port between 1025 and 65535",
+	"log_level":         "{0} must be one of [debug info warn error]",
+	"log_format":        "{0} must be one of [text json]",
+}
+
+// newTranslator returns an English translator with the built-in messages registered for v.
+func newTranslator(v *validator.Validate) ut.Translator {
+	locale := en.New()
+	trans, _ := ut.New(locale, locale).GetTranslator("en")
+	_ = entrans.RegisterDefaultTranslations(v, trans)
+	return trans
+}
+
+// addTranslations registers the messages of the custom validators and aliases with e.
+func (e *engine) addTranslations() {
+	for tag, text := range customTranslations {
+		_ = e.registerTranslation(tag, text, translationArgs)
+	}
+	_ = e.registerTranslation("duration_between", "{0} must be between {1} and {2}", durationBetweenArgs)
+	_ = e.registerTranslation("uri_scheme", "{0} must be a URI with one of the schemes [{1}]", translationArgs)
+}
+
+// RegisterTranslation registers the English message for tag, typically right after registering
+// the tag with RegisterValidation. In text, {0} is replaced with the field name and {1} with the
+// tag parameter. Registering a tag that already has a message replaces it.
+// Example usage:
+//
+//	err := validator.RegisterTranslation("is-even", "{0} must be an even number")
+func (v *validatorStruct) RegisterTranslation(tag, text string) error {
+	return v.register(func(e *engine) error {
+		if err := e.registerTranslation(tag, text, translationArgs); err != nil {
+			return fmt.Errorf("failed to register translation for %s: %w", tag, err)
+		}
+		return nil
+	})
+}
+
+// Translate returns an English message for every failed field in err, e.g. "Port must be greater than 1,024".
+// Tags without a registered translation fall back to the validator message. Messages registered
+// after err was reported apply as well. Errors that aren't
+// validation errors are returned as their Error string. A nil err yields nil.
+// Example usage:
+//
+//	if err := validator.ValidateStruct(conf); err != nil {
+//	    c.JSON(http.StatusBadRequest, gin.H{"errors": validator.Translate(err)})
+//	}
+func (v *validatorStruct) Translate(err error) []string {
+	if err == nil {
+		return nil
+	}
+	st := v.load()
+	var vErr *ValidationError
+	if errors.As(err, &vErr) {
+		msgs := make([]string, 0, len(vErr.fields))
+		for _, fe := range vErr.fields {
+			if fe.err == nil {
+				msgs = append(msgs, fe.Error())
+				continue
+			}
+			if fe.Tag == InternalValidationErrorTag {
+				msgs = append(msgs, fmt.Sprintf("%s could not be validated", fe.err.Field()))
+				continue
+			}
+			trans := fe.trans
+			if trans == nil {
+				trans = st.engine.trans
+			}
+			msgs = append(msgs, st.engine.translate(fe.err, trans))
+		}
+		return msgs
+	}
+	var valErr validator.ValidationErrors
+	if errors.As(err, &valErr) {
+		msgs := make([]string, 0, len(valErr))
+		for _, fe := range valErr {
+			msgs = append(msgs, st.engine.translate(fe, st.engine.trans))
+		}
+		return msgs
+	}
+	return []string{err.Error()}
+}
+
+// registerTranslation adds text for tag to the translator of e, rendering its placeholders with the values
+// returned by args. The message is also kept in e.messages, so errors reported by an engine of an older
+// state are translated with it too.
+func (e *engine) registerTranslation(tag, text string, args func(fe validator.FieldError) []string) error {
+	register := func(ut ut.Translator) error {
+		return ut.Add(tag, text, true)
+	}
+	translate := func(ut ut.Translator, fe validator.FieldError) string {
+		msg, err := ut.T(fe.Tag(), args(fe)...)
+		if err != nil {
+			return fe.Error()
+		}
+		return msg
+	}
+	if err := e.validate.RegisterTranslation(tag, e.trans, register, translate); err != nil {
+		return err
+	}
+	e.messages[tag] = func(fe validator.FieldError) string {
+		return translate(e.trans, fe)
+	}
+	return nil
+}
+
+// translate returns the message of fe, preferring the messages registered with e over the
+// translations of trans, the translator of the engine that reported fe.
+func (e *engine) translate(fe validator.FieldError, trans ut.Translator) string {
+	if msg, ok := e.messages[fe.Tag()]; ok {
+		return msg(fe)
+	}
+	return fe.Translate(trans)
+}
+
+// translationArgs returns the field name and the tag parameter.
+func translationArgs(fe validator.FieldError) []string {
+	return []string{fe.Field(), fe.Param()}
+}
+
+// durationBetweenArgs returns the field name and both bounds of a "min:max" parameter.
+func durationBetweenArgs(fe validator.FieldError) []string {
+	lo, hi, _ := strings.Cut(fe.Param(), ":")
+	return []string{fe.Field(), lo, hi}
+}
@@ -0,0 +1,87 @@
+package val
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTranslate_BuiltIn verifies the English messages of built-in tags.
+func TestTranslate_BuiltIn(t *testing.T) {
+	type TestStruct struct {
+		Name string `validate:"required"`
+		Port int    `validate:"gt=1024"`
+	}
+
+	v := GetValidator()
+	err := v.ValidateStruct(TestStruct{Port: 80})
+	require.Error(t, err)
+	assert.Equal(t, []string{"Name is a required field", "Port must be greater than 1,024"}, v.Translate(err))
+}
+
+// TestTranslate_Custom verifies the English messages of the package's custom tags.
+func TestTranslate_Custom(t *testing.T) {
+	type TestStruct struct {
+		Endpoint string        `validate:"urlprefix"`
+		Port     int           `validate:"unprivileged_port"`
+		Level    string        `validate:"loglevel"`
+		Timeout  time.Duration `validate:"duration_between=100ms:30s"`
+		DBURI    string        `validate:"uri_scheme=postgres sqlite"`
+	}
+
+	v := GetValidator()
+	err := v.ValidateStruct(TestStruct{Endpoint: "ftp://host", Port: 80, Level: "trace", Timeout: time.Minute, DBURI: "ftp://whatever"})
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"Endpoint must start with http:// or https://",
+		"Port must be a port between 1025 and 65535",
+		"Level must be one of [debug info warn error]",
+		"Timeout must be between 100ms and 30s",
+		"DBURI must be a URI with one of the schemes [postgres sqlite]",
+	}, v.Translate(err))
+}
+
+// translationTagSeq makes the tags registered by TestRegisterTranslation unique per run,
+// since translations can't be removed from the singleton.
+var translationTagSeq atomic.Int64
+
+// TestRegisterTranslation verifies that a custom validator can register its message alongside.
+func TestRegisterTranslation(t *testing.T) {
+	v := GetValidator()
+	tag := fmt.Sprintf("multiple_of_%d", translationTagSeq.Add(1))
+	require.NoError(t, v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%3 == 0
+	}))
+
+	// The tag is only known at run time, so the struct type is built with reflection
+	typ := reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: reflect.TypeOf(0), Tag: reflect.StructTag(`validate:"` + tag + `=3"`)},
+	})
+	obj := reflect.New(typ).Elem()
+	obj.Field(0).SetInt(4)
+
+	err := v.ValidateStruct(obj.Interface())
+	require.Error(t, err)
+	msgs := v.Translate(err)
+	require.Len(t, msgs, 1)
+	assert.Contains(t, msgs[0], "failed on the '"+tag+"' tag", "expected the validator message without a translation")
+
+	require.NoError(t, v.RegisterTranslation(tag, "{0} must be a multiple of {1}"))
+	assert.Equal(t, []string{"Value must be a multiple of 3"}, v.Translate(err))
+}
+
+// TestTranslate_OtherErrors verifies Translate for nil and non-validation errors.
+func TestTranslate_OtherErrors(t *testing.T) {
+	v := GetValidator()
+	assert.Nil(t, v.Translate(nil))
+	assert.Equal(t, []string{"boom"}, v.Translate(errors.New("boom")))
+
+	err := v.ValidateStruct(nil)
+	assert.Equal(t, []string{"input is nil"}, v.Translate(err))
+}
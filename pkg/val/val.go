@@ -4,33 +4,78 @@
 package val
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"log/slog"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // Validator defines the methods for validation functionality.
 // It abstracts the underlying validator implementation and makes the package testable.
 type Validator interface {
 	ValidateWithTag(variable any, tag string) error
+	ValidateWithTagCtx(ctx context.Context, variable any, tag string) error
 	ValidateStruct(s any) error
+	ValidateStructCtx(ctx context.Context, s any) error
+	ValidateSlice(s any) error
+	ValidateSliceWithTag(s any, tag string) error
+	ValidateMap(m any) error
+	ValidateMapWithTag(m any, tag string) error
 	RegisterValidation(tag string, fn validator.Func) error
+	RegisterValidationCtx(tag string, fn validator.FuncCtx) error
+	RegisterStructValidation(fn validator.StructLevelFunc, types ...any)
+	RegisterAlias(alias, tags string) error
+	Engine() *validator.Validate
+	RegisterTranslation(tag, text string) error
+	Translate(err error) []string
 }
 
 // singleton holds the single instance of the validator.
 // validatorOnce ensures the singleton is initialized only once, making it thread-safe.
 var (
-	singleton     validatorStruct
+	singleton     *validatorStruct
 	validatorOnce sync.Once
 )
 
 // validatorStruct is a wrapper around the go-playground validator.
 // It encapsulates the core validation logic and methods to interact with the validator.
+// The go-playground validator must not be modified during validation, so validations run
+// on an immutable state without holding a lock. Registrations are applied to the unpublished
+// engines of builder, which the next validation swaps in as a new state; the first registration
+// after that replays the earlier ones onto fresh engines. A batch of registrations thus costs
+// a single replay. Validation functions may call back into the validator.
 type validatorStruct struct {
-	validator *validator.Validate
+	mtx           sync.Mutex            // Serializes registrations, publishing and Configure
+	state         atomic.Pointer[state] // Loaded once by every validation
+	registrations []registration        // Replayed on the engines of every new builder
+	builder       *state                // Engines receiving registrations; nil once published
+	pending       atomic.Bool           // Set while builder holds registrations state lacks
+}
+
+// state is an immutable snapshot of the engines and the configuration of the validator.
+type state struct {
+	engine        *engine      // Reads the validate tag
+	binding       *engine      // Reads gin's binding tag, see UseWithGin
+	recoverPanics bool         // Set by WithPanicRecovery
+	logger        *slog.Logger // Set by WithLogger
+}
+
+// registration applies a registered rule, alias or translation to an engine.
+type registration func(e *engine) error
+
+// engine pairs a go-playground validator with the translator of its messages.
+// A validator reads a single tag name, so every tag name gets its own engine;
+// registrations are applied to all of them.
+type engine struct {
+	validate *validator.Validate
+	trans    ut.Translator
+	messages map[string]func(fe validator.FieldError) string // Custom messages by tag
 }
 
 // GetValidator returns the singleton instance of the Validator interface.
@@ -39,73 +84,265 @@ func GetValidator() Validator {
 	validatorOnce.Do(func() {
 		singleton = newValidator()
 	})
-	return &singleton
+	return singleton
 }
 
 // RegisterValidation registers a custom validation function for a specific tag.
+// It is safe to call at any time: validations in flight keep the rules they started with,
+// later validations see the new rule.
+// Panics of fn propagate to the caller unless panic recovery is enabled with Configure.
 // Example usage:
 //
 //	err := validator.RegisterValidation("is-even", func(fl validator.FieldLevel) bool {
 //	    return fl.Field().Int()%2 == 0
 //	})
 func (v *validatorStruct) RegisterValidation(tag string, fn validator.Func) error {
-	return v.validator.RegisterValidation(tag, fn)
+	return v.RegisterValidationCtx(tag, withoutCtx(fn))
+}
+
+// RegisterValidationCtx registers a custom validation function receiving the context passed to
+// ValidateStructCtx or ValidateWithTagCtx, e.g. to consult a deadline or a tenant ID.
+// Example usage:
+//
+//	err := validator.RegisterValidationCtx("tenant-owned", func(ctx context.Context, fl validator.FieldLevel) bool {
+//	    return fl.Field().String() == ctx.Value(tenantKey{})
+//	})
+func (v *validatorStruct) RegisterValidationCtx(tag string, fn validator.FuncCtx) error {
+	return v.register(func(e *engine) error {
+		return e.validate.RegisterValidationCtx(tag, recoverable(fn))
+	})
+}
+
+// RegisterStructValidation registers a struct-level validation function for the given types,
+// for rules spanning several fields. Errors reported with ReportError are returned as fields of
+// the same *ValidationError as tag failures, named after its fieldName and structFieldName arguments.
+// Like RegisterValidation, it applies to validations started after it returns.
+// Example usage:
+//
+//	validator.RegisterStructValidation(func(sl validator.StructLevel) {
+//	    conf := sl.Current().Interface().(genCfg.HttpConfig)
+//	    if conf.ReadTimeout+conf.WriteTimeout > conf.ShutdownTimeout {
+//	        sl.ReportError(conf.ShutdownTimeout, "http_shutdown_timeout", "ShutdownTimeout", "timeouts_sum", "")
+//	    }
+//	}, genCfg.HttpConfig{})
+func (v *validatorStruct) RegisterStructValidation(fn validator.StructLevelFunc, types ...any) {
+	_ = v.register(func(e *engine) error {
+		e.validate.RegisterStructValidation(fn, types...)
+		return nil
+	})
+}
+
+// RegisterAlias registers alias as a shorthand for the tags expression, e.g. "host_or_ip" for
+// "ip4_addr|hostname_rfc1123". Failed validations report the alias as the tag.
+// Like RegisterValidation, it applies to validations started after it returns.
+// Example usage:
+//
+//	err := validator.RegisterAlias("even_port", "port,is-even")
+func (v *validatorStruct) RegisterAlias(alias, tags string) error {
+	if tags == "" {
+		return fmt.Errorf("failed to register alias %s: tags are empty", alias)
+	}
+	return v.register(func(e *engine) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("failed to register alias %s: %v", alias, r)
+			}
+		}()
+		e.validate.RegisterAlias(alias, tags)
+		return nil
+	})
+}
+
+// Engine returns the underlying go-playground validator for integrations that need it,
+// such as gin's binding package. Treat it as read-only: register rules, aliases and
+// translations through the Validator methods. Registrations replace the engine, so
+// fetch it again after registering.
+func (v *validatorStruct) Engine() *validator.Validate {
+	return v.load().engine.validate
+}
+
+// load returns the state validations run on, publishing pending registrations first.
+func (v *validatorStruct) load() *state {
+	if v.pending.Load() {
+		v.publish()
+	}
+	return v.state.Load()
+}
+
+// publish swaps in a state with the engines of builder. The engines are never modified again,
+// so the next registration starts a new builder.
+func (v *validatorStruct) publish() {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if !v.pending.Load() {
+		return
+	}
+	cur := v.state.Load()
+	v.state.Store(&state{
+		engine:        v.builder.engine,
+		binding:       v.builder.binding,
+		recoverPanics: cur.recoverPanics,
+		logger:        cur.logger,
+	})
+	v.builder = nil
+	v.pending.Store(false)
+}
+
+// engines returns every engine registrations must be applied to.
+func (s *state) engines() []*engine {
+	return []*engine{s.engine, s.binding}
+}
+
+// register applies reg to the engines of builder, starting a new builder replaying the earlier
+// registrations if the last one was published, and records reg. The next validation publishes it.
+// On error the published state is left unchanged.
+func (v *validatorStruct) register(reg registration) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	if v.builder == nil {
+		v.builder = v.newBuilder()
+	}
+	for _, e := range v.builder.engines() {
+		if err := reg(e); err != nil {
+			// reg may have been applied to the other engine
+			v.builder = v.newBuilder()
+			return err
+		}
+	}
+	v.registrations = append(v.registrations, reg)
+	v.pending.Store(true)
+	return nil
+}
+
+// newBuilder returns a state with fresh engines replaying the recorded registrations.
+// The caller must hold mtx.
+func (v *validatorStruct) newBuilder() *state {
+	b := &state{engine: newEngine("validate"), binding: newEngine("binding")}
+	for _, e := range b.engines() {
+		for _, r := range v.registrations {
+			_ = r(e)
+		}
+	}
+	return b
 }
 
 // ValidateWithTag validates a variable using the provided tag.
-// It returns an error if validation fails.
+// It returns a *ValidationError if validation fails.
 // Example usage:
 //
 //	err := validator.ValidateWithTag("test@example.com", "email")
 func (v *validatorStruct) ValidateWithTag(variable any, tag string) error {
-	if err := v.validator.Var(variable, tag); err != nil {
-		return handleValidatorError(err)
+	return v.ValidateWithTagCtx(context.Background(), variable, tag)
+}
+
+// ValidateWithTagCtx validates a variable using the provided tag, passing ctx to validation
+// functions registered with RegisterValidationCtx.
+// It returns a *ValidationError if validation fails.
+// Example usage:
+//
+//	err := validator.ValidateWithTagCtx(ctx, "acme", "tenant-owned")
+func (v *validatorStruct) ValidateWithTagCtx(ctx context.Context, variable any, tag string) error {
+	st := v.load()
+	ctx, rec := st.withPanicRecorder(ctx)
+	if err := st.engine.validate.VarCtx(ctx, variable, tag); err != nil {
+		return rec.mark(handleValidatorError(err, st.engine.trans))
 	}
 	return nil
 }
 
 // ValidateStruct validates a struct based on its tags.
 // It ensures the input is not nil, not empty, and is of type struct.
-// If validation fails, it returns a detailed error.
+// If validation fails, it returns a *ValidationError listing every failed field.
 func (v *validatorStruct) ValidateStruct(s any) error {
+	return v.ValidateStructCtx(context.Background(), s)
+}
+
+// ValidateStructCtx validates a struct based on its tags, passing ctx to validation functions
+// registered with RegisterValidationCtx. Input checks and errors are the same as ValidateStruct.
+func (v *validatorStruct) ValidateStructCtx(ctx context.Context, s any) error {
 	if err := validateStruct(s); err != nil {
 		return err
 	}
 
-	if err := v.validator.Struct(s); err != nil {
-		return handleValidatorError(err)
+	st := v.load()
+	ctx, rec := st.withPanicRecorder(ctx)
+	if err := st.engine.validate.StructCtx(ctx, s); err != nil {
+		return rec.mark(handleValidatorError(err, st.engine.trans))
 	}
 	return nil
 }
 
-// TODO: add unit tests for new validations
-
 // addCustomValidators registers custom validation rules with the validator instance.
 //
 // Custom Validators:
 // - "urlprefix": Validates that a string starts with "http://" or "https://".
+// - "port": Validates a port number between 1 and 65535.
+// - "unprivileged_port": Validates a port number between 1025 and 65535.
+// - "loglevel": Validates one of "debug", "info", "warn" or "error".
+// - "logformat": Validates one of "text" or "json".
+// - "duration_between": Validates a duration within an inclusive range, e.g. "duration_between=100ms:30s".
+// - "uri_scheme": Validates a URI with one of the given schemes, e.g. "uri_scheme=postgresql postgres sqlite file".
+// - "listen_addr": Validates a "host:port" address with an optional host, e.g. "0.0.0.0:8080" or ":8080".
+//
+// Aliases:
+// - "host_or_ip": "ip4_addr|hostname_rfc1123".
+// - "svc_port": "unprivileged_port".
+// - "log_level": "loglevel".
+// - "log_format": "logformat".
 //
 // Parameters:
 // - v (*validator.Validate): The validator instance where custom validations will be registered.
 //
 // This function is intended to be called during validator initialization to
 // ensure the custom rules are consistently available across the application.
+// Like the rules registered with RegisterValidation, they are covered by WithPanicRecovery.
 func addCustomValidators(v *validator.Validate) {
 	fn := func(fl validator.FieldLevel) bool {
 		value := fl.Field().String()
 		return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
 	}
-	_ = v.RegisterValidation("urlprefix", fn)
+	custom := map[string]validator.Func{
+		"urlprefix":         fn,
+		"port":              portBetween(1, 65535),
+		"unprivileged_port": portBetween(1025, 65535),
+		"loglevel":          oneOf("debug", "info", "warn", "error"),
+		"logformat":         oneOf("text", "json"),
+		"duration_between":  durationBetween,
+		"uri_scheme":        uriScheme,
+		"listen_addr":       listenAddr,
+	}
+	for tag, fn := range custom {
+		_ = v.RegisterValidationCtx(tag, recoverable(withoutCtx(fn)))
+	}
+
+	v.RegisterAlias("host_or_ip", "ip4_addr|hostname_rfc1123")
+	v.RegisterAlias("svc_port", "unprivileged_port")
+	v.RegisterAlias("log_level", "loglevel")
+	v.RegisterAlias("log_format", "logformat")
 }
 
-// newValidator initializes and returns a new ValidatorStruct instance.
-// It configures the validator with required struct validation enabled.
-func newValidator() validatorStruct {
-	v := validatorStruct{validator: validator.New(validator.WithRequiredStructEnabled())}
-	addCustomValidators(v.validator)
+// newValidator initializes and returns a new ValidatorStruct instance
+// with engines for the validate and binding tags.
+func newValidator() *validatorStruct {
+	v := &validatorStruct{}
+	v.state.Store(&state{engine: newEngine("validate"), binding: newEngine("binding")})
 	return v
 }
 
+// newEngine returns an engine reading rules from the tagName struct tag. It configures the validator
+// with required struct validation enabled, the custom validators and English translations,
+// naming fields after their json tag, then their mapstructure tag, then their Go name.
+func newEngine(tagName string) *engine {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName(tagName)
+	v.RegisterTagNameFunc(fieldTagName)
+	addCustomValidators(v)
+	e := &engine{validate: v, trans: newTranslator(v), messages: make(map[string]func(fe validator.FieldError) string)}
+	e.addTranslations()
+	return e
+}
+
 // validateStruct ensures the input is valid for struct validation.
 // It checks that the input is not nil, not an uninitialized pointer, and of type struct.
 func validateStruct(s any) error {
@@ -129,15 +366,11 @@ func validateStruct(s any) error {
 }
 
 // handleValidatorError processes and formats validation errors.
-// It extracts detailed field-specific errors for structured reporting.
-func handleValidatorError(err error) error {
+// Failed rules are returned as a *ValidationError translated with trans; any other error is wrapped as unexpected.
+func handleValidatorError(err error, trans ut.Translator) error {
 	var valErr validator.ValidationErrors
 	if errors.As(err, &valErr) {
-		var detailedErrors []string
-		for _, fe := range valErr {
-			detailedErrors = append(detailedErrors, fmt.Sprintf("Field '%s': %s", fe.Field(), fe.Error()))
-		}
-		return errors.New(strings.Join(detailedErrors, ", "))
+		return newValidationError(valErr, trans)
 	}
 	return fmt.Errorf("unexpected validation error: %w", err)
 }
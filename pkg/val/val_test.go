@@ -1,11 +1,17 @@
 package val
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestGetValidatorSingleton verifies that GetValidator always returns the same singleton instance.
@@ -103,12 +109,12 @@ func TestRegisterValidation(t *testing.T) {
 // TestHandleUnexpectedValidatorError verifies the handling of unexpected validation errors.
 func TestHandleUnexpectedValidatorError(t *testing.T) {
 	// Test unexpected error
-	err := handleValidatorError(errors.New("unexpected"))
+	err := handleValidatorError(errors.New("unexpected"), nil)
 	assert.EqualError(t, err, "unexpected validation error: unexpected", "expected formatted error message for unexpected error")
 
 	// Test validation errors
 	valErr := validator.ValidationErrors{}
-	err = handleValidatorError(valErr)
+	err = handleValidatorError(valErr, nil)
 	assert.NotEqual(t, valErr, err, "unexpected validation errors returned is expected to be not of type ValidationErrors")
 }
 
@@ -121,7 +127,8 @@ func TestRegisterValidation_NilCallback(t *testing.T) {
 	assert.Error(t, err, "expected an error when registering a nil validation function")
 }
 
-// TestRegisterValidation_PanicInCallback ensures that panics in custom validation callbacks are handled as expected.
+// TestRegisterValidation_PanicInCallback ensures that panics in custom validation callbacks propagate by default
+// and become a field failure tagged internal_validation_error with panic recovery enabled.
 func TestRegisterValidation_PanicInCallback(t *testing.T) {
 	val := GetValidator()
 
@@ -133,16 +140,116 @@ func TestRegisterValidation_PanicInCallback(t *testing.T) {
 
 	// Define a struct to test the custom validation
 	type TestStruct struct {
-		Value int `validate:"panic-validation"`
+		Value int `json:"value" validate:"panic-validation"`
+		Port  int `validate:"port"`
 	}
 
 	// Test struct that triggers the panic
 	testObj := TestStruct{Value: 5}
 
-	// Validate the struct
+	// Fail-fast mode: validate the struct
 	assert.Panics(t, func() {
 		_ = val.ValidateStruct(testObj)
 	}, "expected validation to panic due to callback panic")
+
+	// Recovery mode
+	var logs bytes.Buffer
+	Configure(WithPanicRecovery(), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	defer Configure()
+
+	err = val.ValidateStruct(testObj)
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	fields := vErr.Fields()
+	require.Len(t, fields, 2)
+	assert.Equal(t, InternalValidationErrorTag, fields[0].Tag)
+	assert.Equal(t, "Value", fields[0].Field)
+	assert.Equal(t, "port", fields[1].Tag, "expected other failures to keep their tag")
+	assert.Contains(t, err.Error(), "Field 'Value': validation function of the 'panic-validation' tag panicked")
+	assert.Equal(t, []string{"value could not be validated", "Port must be a port between 1 and 65535"}, val.Translate(err))
+	assert.Contains(t, logs.String(), "intentional panic in validation")
+
+	err = val.ValidateWithTag(5, "panic-validation")
+	require.True(t, errors.As(err, &vErr))
+	assert.Equal(t, InternalValidationErrorTag, vErr.Fields()[0].Tag)
+
+	err = val.ValidateSlice([]TestStruct{{Port: 80}, {Port: 81}})
+	require.True(t, errors.As(err, &vErr))
+	require.Len(t, vErr.Fields(), 2)
+	assert.Equal(t, "[1].Value", vErr.Fields()[1].Field)
+	assert.Equal(t, InternalValidationErrorTag, vErr.Fields()[1].Tag)
+
+	// Configure without options restores fail-fast mode
+	Configure()
+	assert.Panics(t, func() {
+		_ = val.ValidateStruct(testObj)
+	}, "expected validation to panic after panic recovery is disabled")
+}
+
+// TestPanicRecovery_Dive ensures that recovered panics are matched to the right element of a dived slice.
+func TestPanicRecovery_Dive(t *testing.T) {
+	val := GetValidator()
+	require.NoError(t, val.RegisterValidation("panic-on-odd", func(fl validator.FieldLevel) bool {
+		if fl.Field().Int()%2 == 1 {
+			panic("odd")
+		}
+		return fl.Field().Int() < 10
+	}))
+
+	type TestStruct struct {
+		Values []int `validate:"dive,panic-on-odd"`
+	}
+
+	Configure(WithPanicRecovery())
+	defer Configure()
+
+	err := val.ValidateStruct(TestStruct{Values: []int{2, 12, 3, 14}})
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	var tags []string
+	for _, fe := range vErr.Fields() {
+		tags = append(tags, fe.Field+" "+fe.Tag)
+	}
+	assert.Equal(t, []string{
+		"Values[1] panic-on-odd",
+		"Values[2] " + InternalValidationErrorTag,
+		"Values[3] panic-on-odd",
+	}, tags)
+}
+
+// TestPanicRecovery_Alternatives ensures that the panic of a "|" alternative that fails no field
+// is not attributed to another field, and that a failed "|" tag with a panicking alternative is retagged.
+func TestPanicRecovery_Alternatives(t *testing.T) {
+	val := GetValidator()
+	require.NoError(t, val.RegisterValidation("panic-on-p", func(fl validator.FieldLevel) bool {
+		if strings.HasPrefix(fl.Field().String(), "p") {
+			panic("p")
+		}
+		return false
+	}))
+
+	type TestStruct struct {
+		Passed string `validate:"panic-on-p|alpha"`
+		Failed string `validate:"panic-on-p"`
+		Either string `validate:"panic-on-p|numeric"`
+		Plain  string `validate:"panic-on-p"`
+	}
+
+	Configure(WithPanicRecovery())
+	defer Configure()
+
+	err := val.ValidateStruct(TestStruct{Passed: "pass", Failed: "pfail", Either: "peither", Plain: "lain"})
+	var vErr *ValidationError
+	require.True(t, errors.As(err, &vErr), "expected a *ValidationError, got %T", err)
+	var tags []string
+	for _, fe := range vErr.Fields() {
+		tags = append(tags, fe.Field+" "+fe.Tag)
+	}
+	assert.Equal(t, []string{
+		"Failed " + InternalValidationErrorTag,
+		"Either " + InternalValidationErrorTag,
+		"Plain panic-on-p",
+	}, tags)
 }
 
 // TestValidateStruct_UnsupportedType verifies that ValidateStruct returns an error for unsupported input types.
@@ -170,3 +277,99 @@ func TestValidateStruct_UninitializedPointer(t *testing.T) {
 	assert.Error(t, err, "expected an error when validating an uninitialized pointer")
 	assert.Contains(t, err.Error(), "nil pointer", "expected error message indicating nil pointer")
 }
+
+// TestRegisterValidation_ConcurrentWithValidation ensures that registering rules while other goroutines
+// validate is race-free. Run with -race.
+func TestRegisterValidation_ConcurrentWithValidation(t *testing.T) {
+	type TestStruct struct {
+		Port  int    `validate:"port"`
+		Level string `validate:"log_level"`
+	}
+
+	val := GetValidator()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				tag := fmt.Sprintf("concurrent-%d-%d", i, j)
+				assert.NoError(t, val.RegisterValidation(tag, func(fl validator.FieldLevel) bool { return true }))
+				assert.NoError(t, val.RegisterAlias(tag+"-alias", tag))
+				assert.NoError(t, val.RegisterTranslation(tag, "{0} is invalid"))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.NoError(t, val.ValidateStruct(TestStruct{Port: 8080, Level: "info"}))
+				err := val.ValidateWithTag(0, "port")
+				assert.Error(t, err)
+				assert.Len(t, val.Translate(err), 1)
+				assert.NoError(t, val.ValidateSliceWithTag([]int{1, 2}, "port"))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestRegister_ReplaysOncePerBatch ensures that a batch of registrations is applied to the unpublished
+// engines without replaying the earlier ones, and that published engines are never modified.
+func TestRegister_ReplaysOncePerBatch(t *testing.T) {
+	v := newValidator()
+	applied := 0
+	require.NoError(t, v.register(func(e *engine) error {
+		applied++
+		return nil
+	}))
+	for i := 0; i < 50; i++ {
+		tag := fmt.Sprintf("batch-%d", i)
+		require.NoError(t, v.RegisterValidation(tag, func(fl validator.FieldLevel) bool { return true }))
+	}
+	assert.Equal(t, 2, applied, "expected the batch to be applied to the validate and binding engines once")
+
+	published := v.Engine()
+	assert.NoError(t, v.ValidateWithTag(1, "batch-49"))
+	require.NoError(t, v.RegisterValidation("after-publish", func(fl validator.FieldLevel) bool { return false }))
+	assert.Equal(t, 4, applied, "expected the next registration to replay the earlier ones once")
+	assert.Error(t, v.ValidateWithTag(1, "after-publish"))
+	assert.NoError(t, published.Var(1, "batch-49"))
+	assert.Panics(t, func() { _ = published.Var(1, "after-publish") },
+		"expected the published engine to stay unchanged")
+}
+
+// TestValidate_ReentrantWithRegistration ensures that a validation function calling back into the
+// validator doesn't deadlock while other goroutines register rules.
+func TestValidate_ReentrantWithRegistration(t *testing.T) {
+	val := GetValidator()
+	require.NoError(t, val.RegisterValidation("reentrant_port", func(fl validator.FieldLevel) bool {
+		return val.ValidateWithTag(fl.Field().Interface(), "port") == nil
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.NoError(t, val.RegisterValidation(fmt.Sprintf("reentrant-%d", j), func(fl validator.FieldLevel) bool { return true }))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				assert.NoError(t, val.ValidateWithTag(8080, "reentrant_port"))
+				assert.Error(t, val.ValidateWithTag(0, "reentrant_port"))
+			}
+		}()
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("reentrant validation deadlocked with registrations")
+	}
+}